@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/config"
+	"poc-camera/internal/alert"
+	"poc-camera/internal/clock"
+	"poc-camera/internal/evidence"
+	"poc-camera/internal/incident"
+	"poc-camera/internal/logging"
+	"poc-camera/internal/metrics"
+	"poc-camera/internal/pipeline"
+	"poc-camera/internal/shoplifting"
+	"poc-camera/internal/source"
+)
+
+// camera agrupa o estado de uma fonte: pipeline, análise, zonas e evidências
+type camera struct {
+	id        string
+	log       *slog.Logger // logger com o campo camera
+	source    source.FrameSource
+	detector  *shoplifting.ShopliftingDetector
+	clock     *clock.Frame
+	recorder  *evidence.Recorder
+	incidents *incident.Aggregator
+	store     alert.Sink // observações brutas (banco de incidentes), se houver
+	frames    *framePipeline
+	metrics   *metrics.Camera
+	window    *gocv.Window // janela própria (sem mosaico)
+	display   gocv.Mat     // último frame anotado
+	updated   bool         // display mudou desde a última exibição
+	done      bool         // a fonte acabou
+
+	frameCount    int
+	alertCount    int // comportamentos observados (um por frame enquanto durarem)
+	incidentCount int // incidentes abertos
+}
+
+// setupCameras cria as câmeras configuradas, todas usando o mesmo detector de
+// objetos (seguro para uso concorrente, ex: YOLODetectorPool) e registrando
+// métricas em stats. ready é sinalizado quando qualquer câmera tem frame inferido.
+func setupCameras(cfg *config.Config, detector shoplifting.ObjectDetector, classNames []string, stats *metrics.Metrics, ready chan struct{}) ([]*camera, error) {
+	list := cfg.CameraList()
+	var cameras []*camera
+	fail := func(err error) ([]*camera, error) {
+		for _, cam := range cameras {
+			cam.Close()
+		}
+		return nil, err
+	}
+
+	for _, entry := range list {
+		camCfg := cfg.ForCamera(entry)
+		cam := &camera{
+			id:      camCfg.CameraID,
+			log:     slog.With(logging.Camera(camCfg.CameraID)),
+			metrics: stats.Camera(camCfg.CameraID),
+			incidents: incident.NewAggregator(incident.AggregatorOptions{
+				CloseAfter:     time.Duration(cfg.IncidentCloseAfter * float64(time.Second)),
+				UpdateInterval: time.Duration(cfg.IncidentUpdateInterval * float64(time.Second)),
+			}),
+			display: gocv.NewMat(),
+		}
+
+		var err error
+		cam.detector, err = shoplifting.NewShopliftingDetector(detector, camCfg, classNames)
+		if err != nil {
+			cam.display.Close()
+			return fail(fmt.Errorf("câmera %s: %v", cam.id, err))
+		}
+		cameras = append(cameras, cam)
+
+		cam.log.Info("Configurando câmera", logging.Icon("📷"))
+		cam.source, err = setupSource(camCfg)
+		if err != nil {
+			return fail(fmt.Errorf("câmera %s: %v", cam.id, err))
+		}
+		cam.clock = setupClock(camCfg, cam.source, cam.detector)
+
+		// Evidências em subdiretório por câmera quando há mais de uma
+		if cfg.EvidenceDir != "" {
+			dir := cfg.EvidenceDir
+			if len(list) > 1 {
+				dir = filepath.Join(dir, cam.id)
+			}
+			cam.recorder, err = evidence.NewRecorder(evidence.Options{
+				Dir:            dir,
+				PreRollFrames:  cfg.EvidencePreRollFrames,
+				PostRollFrames: cfg.EvidencePostRollFrames,
+				ClipFPS:        cfg.EvidenceClipFPS,
+				MaxFiles:       cfg.EvidenceMaxFiles,
+				MaxBytes:       int64(cfg.EvidenceMaxSizeMB) * 1024 * 1024,
+				MaxAge:         time.Duration(cfg.EvidenceMaxAgeHours * float64(time.Hour)),
+			})
+			if err != nil {
+				return fail(fmt.Errorf("câmera %s: %v", cam.id, err))
+			}
+			cam.log.Info("Evidências em disco", logging.Icon("🎞️ "), slog.String("dir", dir))
+		}
+
+		schedule := pipeline.NewScheduler(cfg.DetectionStride, cfg.DetectionMaxStride,
+			time.Duration(cfg.DetectionTargetLatency*float64(time.Millisecond)))
+		cam.frames = newFramePipeline(cam.source, detector, cfg.PipelineQueueSize, schedule, cam.metrics, ready)
+	}
+	return cameras, nil
+}
+
+// process analisa um frame inferido, envia os alertas e, com render,
+// desenha o resultado e o guarda para exibição
+func (c *camera) process(frame inferredFrame, sink alert.Sink, render bool) {
+	analysisStart := time.Now()
+
+	c.frameCount++
+	if c.clock != nil {
+		c.clock.Tick(frame.pts, frame.hasPTS)
+	}
+	img := frame.img
+
+	// Executa a análise de shoplifting sobre as detecções da inferência ou,
+	// em frames sem detecção, sobre a previsão do tracker
+	var detections []shoplifting.DetectionResult
+	var suspiciousBehaviors []shoplifting.SuspiciousBehavior
+	if frame.detected {
+		detections, suspiciousBehaviors = c.detector.Analyze(frame.detections)
+		for _, d := range frame.detections {
+			c.metrics.Detection(d.ClassID)
+		}
+	} else {
+		detections, suspiciousBehaviors = c.detector.Interpolate()
+	}
+
+	// Agrega os comportamentos do frame em incidentes: só aberturas,
+	// atualizações e encerramentos seguem para os sinks
+	c.alertCount += len(suspiciousBehaviors)
+	now := c.detector.Now()
+	var events, observations []alert.Alert
+	for _, behavior := range suspiciousBehaviors {
+		obs := newAlert(behavior, frame.number, c.source.Name(), img, now)
+		obs.CameraID = c.id
+		if ev, ok := c.incidents.Observe(obs); ok {
+			events = append(events, ev)
+		}
+		// Observações também vão ao banco, no ritmo do log (uma por segundo
+		// por tipo e trilha)
+		if c.store != nil && behavior.ShouldLog {
+			obs.IncidentID = c.incidents.IncidentID(obs)
+			observations = append(observations, obs)
+		}
+	}
+	events = append(events, c.incidents.Expire(now)...)
+
+	// Frames anotados servem à janela e às evidências
+	if render || c.recorder != nil {
+		// Desenha zonas e resultados na imagem
+		c.detector.DrawZones(&img)
+		shoplifting.DrawShopliftingDetections(&img, detections, suspiciousBehaviors)
+		c.detector.DrawRiskScores(&img)
+
+		// Adiciona informações de status e latência na imagem
+		addStatusInfo(&img, c.frameCount, len(detections), len(suspiciousBehaviors), c.incidentCount)
+		addLatencyInfo(&img, c.frames.latency.Snapshot(), frame.stride)
+	}
+
+	// Grava evidências na abertura e nas atualizações dos incidentes
+	// (snapshot + clipe com pré/pós-alerta)
+	if c.recorder != nil {
+		c.recorder.Push(img)
+		for i := range events {
+			if events[i].Event == alert.EventClosed {
+				continue
+			}
+			ev, err := c.recorder.Capture(events[i].PersonID, events[i].Type, img, events[i].Timestamp)
+			if err != nil {
+				c.log.Warn("Erro ao gravar evidência", logging.TrackID(events[i].PersonID), logging.Err(err))
+			}
+			events[i].SnapshotPath = ev.Snapshot
+			events[i].ClipPath = ev.Clip
+		}
+	}
+
+	c.emit(events, sink)
+	for _, obs := range observations {
+		if err := c.store.Send(obs); err != nil {
+			c.log.Warn("Erro ao gravar observação", logging.TrackID(obs.PersonID), logging.Behavior(obs.Type), logging.Err(err))
+		}
+	}
+
+	// Métricas do frame
+	stats := c.detector.TrackingStats()
+	c.metrics.Frame(time.Now())
+	c.metrics.Tracks(stats.ActiveTracks, stats.TrackedPeople)
+	c.metrics.Dropped(c.frames.Dropped())
+
+	c.frames.latency.Observe(pipeline.StageAnalysis, time.Since(analysisStart))
+	c.frames.latency.Observe(pipeline.StageTotal, time.Since(frame.capturedAt))
+
+	// O frame anotado substitui o anterior na exibição
+	if render {
+		c.display.Close()
+		c.display = img
+		c.updated = true
+		return
+	}
+	img.Close()
+}
+
+// closeIncidents encerra os incidentes ainda abertos (fim da execução)
+func (c *camera) closeIncidents(sink alert.Sink) {
+	c.emit(c.incidents.Flush(), sink)
+}
+
+// emit registra os eventos de incidentes no log e nas métricas e os envia
+// aos sinks
+func (c *camera) emit(events []alert.Alert, sink alert.Sink) {
+	for _, ev := range events {
+		attrs := []any{
+			logging.Frame(ev.Frame), logging.TrackID(ev.PersonID), logging.Behavior(ev.Type),
+			slog.String("incident_id", ev.IncidentID),
+		}
+		switch ev.Event {
+		case alert.EventOpened:
+			c.incidentCount++
+			c.metrics.Alert(ev.Type)
+			attrs = append(attrs, logging.Icon("🚨"), logging.Confidence(ev.Confidence),
+				slog.String("description", ev.Description))
+			if ev.Details != "" {
+				attrs = append(attrs, slog.String("details", ev.Details))
+			}
+			c.log.Warn("Incidente aberto", attrs...)
+		case alert.EventUpdated:
+			attrs = append(attrs, logging.Icon("📈"), slog.Float64("peak_confidence", roundConfidence(ev.PeakConfidence)),
+				slog.String("description", ev.Description))
+			c.log.Warn("Incidente atualizado", attrs...)
+		case alert.EventClosed:
+			attrs = append(attrs, logging.Icon("✅"), slog.Float64("peak_confidence", roundConfidence(ev.PeakConfidence)),
+				slog.Float64("duration_seconds", math.Round(ev.DurationSeconds*10)/10))
+			c.log.Info("Incidente encerrado", attrs...)
+		}
+
+		if err := sink.Send(ev); err != nil {
+			c.log.Warn("Erro ao enviar alerta", logging.TrackID(ev.PersonID), logging.Behavior(ev.Type), logging.Err(err))
+		}
+	}
+}
+
+// roundConfidence arredonda uma confiança para o log
+func roundConfidence(c float32) float64 {
+	return math.Round(float64(c)*1000) / 1000
+}
+
+// logStats registra as estatísticas finais da câmera e a latência por estágio
+func (c *camera) logStats() {
+	droppedCapture, droppedAnalysis := c.frames.Dropped()
+	attrs := []any{
+		logging.Icon("📊"),
+		slog.Int("frames", c.frameCount),
+		slog.Int("alerts", c.alertCount),
+		slog.Int("incidents", c.incidentCount),
+		slog.Int("evicted_tracks", c.detector.TrackingStats().Evictions),
+		slog.Int64("dropped_before_inference", droppedCapture),
+		slog.Int64("dropped_before_analysis", droppedAnalysis),
+	}
+	if c.frameCount > 0 {
+		rate := float64(c.alertCount) / float64(c.frameCount) * 100
+		attrs = append(attrs, slog.Float64("alert_rate_pct", math.Round(rate*100)/100))
+	}
+	c.log.Info("Estatísticas finais", attrs...)
+
+	for _, s := range c.frames.latency.Snapshot() {
+		c.log.Info("Latência por estágio", logging.Icon("⏱️ "), slog.String("stage", s.Stage),
+			slog.Duration("mean", s.Mean.Round(time.Millisecond/10)),
+			slog.Duration("max", s.Max.Round(time.Millisecond/10)),
+			slog.Int("count", s.Count))
+	}
+}
+
+// Close libera os recursos da câmera (o pipeline já deve estar parado)
+func (c *camera) Close() {
+	if c.window != nil {
+		c.window.Close()
+	}
+	c.display.Close()
+	if c.recorder != nil {
+		c.recorder.Close()
+	}
+	if c.source != nil {
+		c.source.Close()
+	}
+	c.detector.Close()
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClassOverride sobrescreve os limites de detecção de uma classe
+type ClassOverride struct {
+	Name                string  `yaml:"name" json:"name" toml:"name"`
+	ConfidenceThreshold float32 `yaml:"confidence_threshold" json:"confidence_threshold" toml:"confidence_threshold"` // 0 = confidence_threshold global
+	MinObjectSize       int     `yaml:"min_object_size" json:"min_object_size" toml:"min_object_size"`                // 0 = min_object_size global
+}
+
+// ClassRemap junta várias classes do modelo sob um único rótulo. Se To for
+// uma classe existente, as classes de From passam a ser ela; caso contrário,
+// a primeira classe de From é renomeada para To e recebe as demais.
+type ClassRemap struct {
+	From []string `yaml:"from" json:"from" toml:"from"`
+	To   string   `yaml:"to" json:"to" toml:"to"`
+}
+
+// ClassTable é a política de classes resolvida contra o arquivo de classes.
+// IDs "finais" são os IDs do modelo após o remapeamento.
+type ClassTable struct {
+	Names      []string  // rótulo de cada ID final
+	Remap      []int     // ID do modelo → ID final
+	Enabled    []bool    // classes mantidas (allow/deny), por ID final
+	Thresholds []float32 // confiança mínima por ID final
+	MinSizes   []int     // tamanho mínimo por ID final
+}
+
+// classIndex resolve nomes de classe considerando o remapeamento
+type classIndex struct {
+	names    []string         // rótulos após o remapeamento
+	remap    []int            // ID do modelo → ID final
+	original map[string][]int // nome do modelo → todos os IDs com o nome
+}
+
+// newClassIndex aplica class_remap sobre os nomes do modelo
+func (c *Config) newClassIndex(classNames []string) (*classIndex, error) {
+	ix := &classIndex{
+		names:    append([]string(nil), classNames...),
+		remap:    make([]int, len(classNames)),
+		original: make(map[string][]int, len(classNames)),
+	}
+	for id, name := range classNames {
+		ix.remap[id] = id
+		key := normalizeClassName(name)
+		ix.original[key] = append(ix.original[key], id)
+	}
+
+	var missing []string
+	for _, r := range c.ClassRemap {
+		// Todas as ocorrências de cada nome (o arquivo tem nomes repetidos)
+		var sources []int
+		for _, from := range r.From {
+			ids, found := ix.original[normalizeClassName(from)]
+			if !found {
+				missing = append(missing, fmt.Sprintf("%q", from))
+			}
+			sources = append(sources, ids...)
+		}
+		if len(sources) == 0 {
+			continue
+		}
+
+		var target int
+		if ids, exists := ix.original[normalizeClassName(r.To)]; exists {
+			target = ids[0]
+		} else {
+			target = sources[0]
+			ix.names[target] = r.To
+		}
+		for _, id := range sources {
+			ix.remap[id] = target
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("classes de class_remap não encontradas em %s: %s",
+			c.ClassNamesFile, strings.Join(missing, ", "))
+	}
+	return ix, nil
+}
+
+// lookupAll retorna todos os IDs finais de um nome, em ordem crescente:
+// classes repetidas no arquivo e classes remapeadas para o rótulo contam
+// todas, não só a primeira
+func (ix *classIndex) lookupAll(name string) []int {
+	key := normalizeClassName(name)
+	seen := make(map[int]bool)
+	for id, label := range ix.names {
+		if ix.remap[id] == id && normalizeClassName(label) == key {
+			seen[id] = true
+		}
+	}
+	for _, id := range ix.original[key] {
+		seen[ix.remap[id]] = true
+	}
+
+	ids := make([]int, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// ResolveClasses monta a tabela de classes (remapeamento, allow/deny e
+// limites por classe) a partir dos nomes do modelo. Retorna erro listando
+// todos os nomes que não existem no arquivo de classes.
+func (c *Config) ResolveClasses(classNames []string) (*ClassTable, error) {
+	ix, err := c.newClassIndex(classNames)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(classNames)
+	table := &ClassTable{
+		Names:      ix.names,
+		Remap:      ix.remap,
+		Enabled:    make([]bool, n),
+		Thresholds: make([]float32, n),
+		MinSizes:   make([]int, n),
+	}
+	for id := range classNames {
+		table.Enabled[id] = len(c.ClassAllow) == 0
+		table.Thresholds[id] = c.ConfidenceThreshold
+		table.MinSizes[id] = c.MinObjectSize
+	}
+
+	// Nomes repetidos no arquivo de classes valem para todos os seus IDs
+	var missing []string
+	resolve := func(name string) []int {
+		ids := ix.lookupAll(name)
+		if len(ids) == 0 {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+		return ids
+	}
+
+	for _, name := range c.ClassAllow {
+		for _, id := range resolve(name) {
+			table.Enabled[id] = true
+		}
+	}
+	for _, name := range c.ClassDeny {
+		for _, id := range resolve(name) {
+			table.Enabled[id] = false
+		}
+	}
+	for _, o := range c.ClassOverrides {
+		for _, id := range resolve(o.Name) {
+			if o.ConfidenceThreshold > 0 {
+				table.Thresholds[id] = o.ConfidenceThreshold
+			}
+			if o.MinObjectSize > 0 {
+				table.MinSizes[id] = o.MinObjectSize
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("classes não encontradas em %s: %s",
+			c.ClassNamesFile, strings.Join(missing, ", "))
+	}
+	return table, nil
+}
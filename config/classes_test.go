@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveClassesRepeatedNames(t *testing.T) {
+	// "tênis" aparece nos IDs 1 e 3, como em object365.names
+	classNames := []string{"pessoa", "tênis", "celular", "tênis"}
+
+	tests := []struct {
+		name           string
+		configure      func(*Config)
+		wantEnabled    []bool
+		wantThresholds []float32
+		wantMinSizes   []int
+	}{
+		{
+			name:           "deny desabilita todas as ocorrências",
+			configure:      func(c *Config) { c.ClassDeny = []string{"tênis"} },
+			wantEnabled:    []bool{true, false, true, false},
+			wantThresholds: []float32{0.25, 0.25, 0.25, 0.25},
+			wantMinSizes:   []int{20, 20, 20, 20},
+		},
+		{
+			name:           "allow habilita todas as ocorrências",
+			configure:      func(c *Config) { c.ClassAllow = []string{"pessoa", "tênis"} },
+			wantEnabled:    []bool{true, true, false, true},
+			wantThresholds: []float32{0.25, 0.25, 0.25, 0.25},
+			wantMinSizes:   []int{20, 20, 20, 20},
+		},
+		{
+			name: "override vale para todas as ocorrências",
+			configure: func(c *Config) {
+				c.ClassOverrides = []ClassOverride{{Name: "Tênis", ConfidenceThreshold: 0.1, MinObjectSize: 8}}
+			},
+			wantEnabled:    []bool{true, true, true, true},
+			wantThresholds: []float32{0.25, 0.1, 0.25, 0.1},
+			wantMinSizes:   []int{20, 8, 20, 8},
+		},
+		{
+			// As duas ocorrências viram o ID 1; regras pelo nome antigo ou novo
+			// valem para o ID final
+			name: "deny pelo nome antigo de classe remapeada",
+			configure: func(c *Config) {
+				c.ClassRemap = []ClassRemap{{From: []string{"tênis"}, To: "calçado"}}
+				c.ClassDeny = []string{"tênis"}
+			},
+			wantEnabled:    []bool{true, false, true, true},
+			wantThresholds: []float32{0.25, 0.25, 0.25, 0.25},
+			wantMinSizes:   []int{20, 20, 20, 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ConfidenceThreshold = 0.25
+			cfg.MinObjectSize = 20
+			tt.configure(cfg)
+
+			table, err := cfg.ResolveClasses(classNames)
+			if err != nil {
+				t.Fatalf("ResolveClasses: %v", err)
+			}
+			if !reflect.DeepEqual(table.Enabled, tt.wantEnabled) {
+				t.Errorf("habilitadas = %v, esperado %v", table.Enabled, tt.wantEnabled)
+			}
+			if !reflect.DeepEqual(table.Thresholds, tt.wantThresholds) {
+				t.Errorf("limiares = %v, esperado %v", table.Thresholds, tt.wantThresholds)
+			}
+			if !reflect.DeepEqual(table.MinSizes, tt.wantMinSizes) {
+				t.Errorf("tamanhos mínimos = %v, esperado %v", table.MinSizes, tt.wantMinSizes)
+			}
+		})
+	}
+}
+
+func TestResolveClassesMissing(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ClassDeny = []string{"drone"}
+	cfg.ClassOverrides = []ClassOverride{{Name: "perfume", ConfidenceThreshold: 0.5}}
+
+	_, err := cfg.ResolveClasses([]string{"pessoa", "celular"})
+	if err == nil {
+		t.Fatal("esperado erro para classes inexistentes")
+	}
+	for _, want := range []string{`"drone"`, `"perfume"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("erro %q não menciona %s", err, want)
+		}
+	}
+}
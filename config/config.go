@@ -1,31 +1,136 @@
 package config
 
-// Config centraliza todas as configurações do sistema
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Config centraliza todas as configurações do sistema.
+// As chaves das tags são usadas no arquivo de configuração (YAML/JSON/TOML),
+// nas variáveis de ambiente (POC_CAMERA_<CHAVE>) e nas flags (-chave-com-hifens).
 type Config struct {
 	// Modelos
-	ObjectDetectionModel string
-	ClassNamesFile       string
+	ObjectDetectionModel string `yaml:"object_detection_model" json:"object_detection_model" toml:"object_detection_model" usage:"modelo ONNX de detecção de objetos"`
+	ClassNamesFile       string `yaml:"class_names_file" json:"class_names_file" toml:"class_names_file" usage:"arquivo com os nomes das classes"`
+
+	// Fonte de vídeo
+	SourceType string `yaml:"source_type" json:"source_type" toml:"source_type" usage:"tipo da fonte: auto, device, file, stream ou images"`
+	Source     string `yaml:"source" json:"source" toml:"source" usage:"índice da câmera, arquivo de vídeo, URL RTSP/HTTP ou diretório de imagens"`
+	CameraID   string `yaml:"camera_id" json:"camera_id" toml:"camera_id" usage:"identificador da câmera nos alertas (sem a lista cameras)"`
 
 	// Thresholds de detecção
-	ConfidenceThreshold float32
-	NMSThreshold        float32
-	MinObjectSize       int
+	ConfidenceThreshold float32 `yaml:"confidence_threshold" json:"confidence_threshold" toml:"confidence_threshold" usage:"confiança mínima de detecção (0..1)"`
+	NMSThreshold        float32 `yaml:"nms_threshold" json:"nms_threshold" toml:"nms_threshold" usage:"limiar de IoU do Non-Maximum Suppression (0..1)"`
+	NMSStrategy         string  `yaml:"nms_strategy" json:"nms_strategy" toml:"nms_strategy" usage:"estratégia de NMS: global, class_aware ou soft"`
+	NMSSoftSigma        float64 `yaml:"nms_soft_sigma" json:"nms_soft_sigma" toml:"nms_soft_sigma" usage:"sigma do decaimento gaussiano do Soft-NMS"`
+	MaxDetections       int     `yaml:"max_detections" json:"max_detections" toml:"max_detections" usage:"máximo de detecções por frame após o NMS (0 = sem limite)"`
+	MinObjectSize       int     `yaml:"min_object_size" json:"min_object_size" toml:"min_object_size" usage:"tamanho mínimo dos objetos em pixels"`
 
 	// Configurações de shoplifting
-	HidingBehaviorThreshold    float32
-	LoiteringTimeThreshold     float64
-	ProximityThreshold         float64
+	HidingBehaviorThreshold float32 `yaml:"hiding_behavior_threshold" json:"hiding_behavior_threshold" toml:"hiding_behavior_threshold" usage:"limiar para comportamento de ocultação (0..1)"`
+	LoiteringTimeThreshold  float64 `yaml:"loitering_time_threshold" json:"loitering_time_threshold" toml:"loitering_time_threshold" usage:"tempo limite de permanência em segundos"`
+	ProximityThreshold      float64 `yaml:"proximity_threshold" json:"proximity_threshold" toml:"proximity_threshold" usage:"distância de proximidade suspeita em pixels"`
+
+	// Pontuação de risco por pessoa (comportamentos fundidos com pesos e decaimento)
+	RiskWeightLoitering float64 `yaml:"risk_weight_loitering" json:"risk_weight_loitering" toml:"risk_weight_loitering" usage:"peso da permanência excessiva na pontuação de risco (por segundo)"`
+	RiskWeightProximity float64 `yaml:"risk_weight_proximity" json:"risk_weight_proximity" toml:"risk_weight_proximity" usage:"peso da proximidade com itens valiosos na pontuação de risco (por segundo)"`
+	RiskWeightMovement  float64 `yaml:"risk_weight_movement" json:"risk_weight_movement" toml:"risk_weight_movement" usage:"peso de cada movimento suspeito na pontuação de risco"`
+	RiskWeightZone      float64 `yaml:"risk_weight_zone" json:"risk_weight_zone" toml:"risk_weight_zone" usage:"peso dos eventos de zona na pontuação de risco (acesso restrito por segundo, saída sem caixa por evento)"`
+	RiskHalfLife        float64 `yaml:"risk_half_life" json:"risk_half_life" toml:"risk_half_life" usage:"meia-vida da pontuação de risco em segundos (0 = sem decaimento)"`
+	RiskThreshold       float64 `yaml:"risk_threshold" json:"risk_threshold" toml:"risk_threshold" usage:"pontuação de risco que gera alerta RISCO_ELEVADO (0..1, 0 = sem alerta)"`
 
 	// Interface
-	WindowName        string
-	InputSize         int
-	NumDetections     int
-	NumAttributes     int
-	MaxValidClassID   int
+	Headless        bool   `yaml:"headless" json:"headless" toml:"headless" usage:"executa sem janela (servidores sem display)"`
+	WindowName      string `yaml:"window_name" json:"window_name" toml:"window_name" usage:"título da janela"`
+	InputSize       int    `yaml:"input_size" json:"input_size" toml:"input_size" usage:"tamanho da entrada do modelo"`
+	NumDetections   int    `yaml:"num_detections" json:"num_detections" toml:"num_detections" usage:"número de detecções na saída do modelo"`
+	NumAttributes   int    `yaml:"num_attributes" json:"num_attributes" toml:"num_attributes" usage:"atributos por detecção (4 coordenadas + classes; +1 com objectness; 6 em end_to_end)"`
+	MaxValidClassID int    `yaml:"max_valid_class_id" json:"max_valid_class_id" toml:"max_valid_class_id" usage:"maior ID de classe válido; classes acima dele são descartadas (0 = só pessoas)"`
+	OutputLayout    string `yaml:"output_layout" json:"output_layout" toml:"output_layout" usage:"layout da saída do modelo: transposed, row_major, objectness ou end_to_end"`
+	Letterbox       bool   `yaml:"letterbox" json:"letterbox" toml:"letterbox" usage:"redimensiona preservando a proporção, com bordas até a entrada quadrada"`
+	LetterboxColor  string `yaml:"letterbox_color" json:"letterbox_color" toml:"letterbox_color" usage:"cor das bordas do letterbox em R,G,B"`
+	Mosaic          bool   `yaml:"mosaic" json:"mosaic" toml:"mosaic" usage:"mostra todas as câmeras em uma única janela em grade"`
+	MosaicColumns   int    `yaml:"mosaic_columns" json:"mosaic_columns" toml:"mosaic_columns" usage:"colunas do mosaico (0 = automático)"`
+	MosaicTileWidth int    `yaml:"mosaic_tile_width" json:"mosaic_tile_width" toml:"mosaic_tile_width" usage:"largura de cada câmera no mosaico em pixels"`
+
+	// Logs
+	LogFormat string `yaml:"log_format" json:"log_format" toml:"log_format" usage:"formato dos logs: console (legível, com ícones), text ou json"`
+	LogLevel  string `yaml:"log_level" json:"log_level" toml:"log_level" usage:"nível mínimo dos logs: debug, info, warn ou error"`
+
+	// Pipeline (captura, inferência e análise em estágios concorrentes)
+	PipelineQueueSize int `yaml:"pipeline_queue_size" json:"pipeline_queue_size" toml:"pipeline_queue_size" usage:"frames em espera entre estágios do pipeline (fontes ao vivo descartam o mais antigo)"`
+	DetectorPoolSize  int `yaml:"detector_pool_size" json:"detector_pool_size" toml:"detector_pool_size" usage:"instâncias da rede para inferência concorrente"`
+	DetectorBatchSize int `yaml:"detector_batch_size" json:"detector_batch_size" toml:"detector_batch_size" usage:"máximo de frames por inferência em lote (>1 exige ONNX com batch dinâmico)"`
+
+	// Taxa de detecção (frames sem detecção seguem a previsão do tracker)
+	DetectionStride        int     `yaml:"detection_stride" json:"detection_stride" toml:"detection_stride" usage:"roda a detecção a cada N frames"`
+	DetectionTargetLatency float64 `yaml:"detection_target_latency" json:"detection_target_latency" toml:"detection_target_latency" usage:"latência alvo da inferência em ms; acima dela o passo aumenta (0 = passo fixo)"`
+	DetectionMaxStride     int     `yaml:"detection_max_stride" json:"detection_max_stride" toml:"detection_max_stride" usage:"passo máximo no modo adaptativo"`
+
+	// Alertas (sinks vazios ficam desabilitados)
+	AlertFile             string  `yaml:"alert_file" json:"alert_file" toml:"alert_file" usage:"arquivo JSON Lines para gravar alertas"`
+	AlertWebhookURL       string  `yaml:"alert_webhook_url" json:"alert_webhook_url" toml:"alert_webhook_url" usage:"URL que recebe alertas via HTTP POST"`
+	AlertWebhookTimeout   float64 `yaml:"alert_webhook_timeout" json:"alert_webhook_timeout" toml:"alert_webhook_timeout" usage:"timeout de cada requisição do webhook em segundos"`
+	AlertWebhookRetries   int     `yaml:"alert_webhook_retries" json:"alert_webhook_retries" toml:"alert_webhook_retries" usage:"novas tentativas com backoff exponencial"`
+	AlertWebhookQueueSize int     `yaml:"alert_webhook_queue_size" json:"alert_webhook_queue_size" toml:"alert_webhook_queue_size" usage:"alertas aguardando envio ao webhook"`
+	AlertMQTTBroker       string  `yaml:"alert_mqtt_broker" json:"alert_mqtt_broker" toml:"alert_mqtt_broker" usage:"broker MQTT (ex: tcp://localhost:1883)"`
+	AlertMQTTTopic        string  `yaml:"alert_mqtt_topic" json:"alert_mqtt_topic" toml:"alert_mqtt_topic" usage:"tópico MQTT dos alertas"`
+	AlertMQTTClientID     string  `yaml:"alert_mqtt_client_id" json:"alert_mqtt_client_id" toml:"alert_mqtt_client_id" usage:"client ID MQTT"`
+
+	// Incidentes (comportamentos agregados em aberto/atualizado/encerrado)
+	IncidentCloseAfter     float64 `yaml:"incident_close_after" json:"incident_close_after" toml:"incident_close_after" usage:"segundos sem observar o comportamento até encerrar o incidente"`
+	IncidentUpdateInterval float64 `yaml:"incident_update_interval" json:"incident_update_interval" toml:"incident_update_interval" usage:"intervalo mínimo em segundos entre atualizações de um incidente"`
+	IncidentDB             string  `yaml:"incident_db" json:"incident_db" toml:"incident_db" usage:"arquivo SQLite onde os incidentes são persistidos"`
+
+	// Evidências (snapshot + clipe por alerta; diretório vazio = desabilitado)
+	EvidenceDir            string  `yaml:"evidence_dir" json:"evidence_dir" toml:"evidence_dir" usage:"diretório para snapshots e clipes dos alertas"`
+	EvidencePreRollFrames  int     `yaml:"evidence_pre_roll_frames" json:"evidence_pre_roll_frames" toml:"evidence_pre_roll_frames" usage:"frames anteriores ao alerta no clipe"`
+	EvidencePostRollFrames int     `yaml:"evidence_post_roll_frames" json:"evidence_post_roll_frames" toml:"evidence_post_roll_frames" usage:"frames posteriores ao alerta no clipe"`
+	EvidenceClipFPS        float64 `yaml:"evidence_clip_fps" json:"evidence_clip_fps" toml:"evidence_clip_fps" usage:"taxa de quadros dos clipes"`
+	EvidenceMaxFiles       int     `yaml:"evidence_max_files" json:"evidence_max_files" toml:"evidence_max_files" usage:"máximo de arquivos de evidência (0 = sem limite)"`
+	EvidenceMaxSizeMB      int     `yaml:"evidence_max_size_mb" json:"evidence_max_size_mb" toml:"evidence_max_size_mb" usage:"tamanho máximo do diretório de evidências em MB (0 = sem limite)"`
+	EvidenceMaxAgeHours    float64 `yaml:"evidence_max_age_hours" json:"evidence_max_age_hours" toml:"evidence_max_age_hours" usage:"idade máxima das evidências em horas (0 = sem limite)"`
+
+	// Métricas Prometheus (endereço vazio = desabilitado)
+	MetricsAddr string `yaml:"metrics_addr" json:"metrics_addr" toml:"metrics_addr" usage:"endereço do servidor de métricas Prometheus (ex: :9090)"`
+
+	// Avaliação offline (anotações vazias = modo normal)
+	EvalAnnotations  string  `yaml:"eval_annotations" json:"eval_annotations" toml:"eval_annotations" usage:"arquivo JSON de anotações ground-truth; ativa o modo de avaliação"`
+	EvalIoUThreshold float64 `yaml:"eval_iou_threshold" json:"eval_iou_threshold" toml:"eval_iou_threshold" usage:"IoU mínimo para casar predição e ground-truth (0..1)"`
+	EvalReport       string  `yaml:"eval_report" json:"eval_report" toml:"eval_report" usage:"arquivo JSON para salvar o relatório de avaliação"`
+
+	// Zonas da loja (somente via arquivo de configuração)
+	Zones []Zone `yaml:"zones" json:"zones" toml:"zones"`
+
+	// Câmeras (somente via arquivo de configuração; vazio = uma câmera com
+	// source, source_type, camera_id e zones)
+	Cameras []Camera `yaml:"cameras" json:"cameras" toml:"cameras"`
+
+	// Filtros de classe (somente via arquivo de configuração)
+	ClassAllow     []string        `yaml:"class_allow" json:"class_allow" toml:"class_allow"` // vazio = todas as classes
+	ClassDeny      []string        `yaml:"class_deny" json:"class_deny" toml:"class_deny"`
+	ClassOverrides []ClassOverride `yaml:"class_overrides" json:"class_overrides" toml:"class_overrides"`
+	ClassRemap     []ClassRemap    `yaml:"class_remap" json:"class_remap" toml:"class_remap"`
+
+	// Catálogo de itens valiosos (somente via arquivo de configuração)
+	ValuableItems []ValuableItem `yaml:"valuable_items" json:"valuable_items" toml:"valuable_items"`
 
 	// Performance
-	MaxTrackedPeople   int
-	TrackerTimeout     float64
+	MaxTrackedPeople int     `yaml:"max_tracked_people" json:"max_tracked_people" toml:"max_tracked_people" usage:"máximo de pessoas rastreadas simultaneamente"`
+	TrackerTimeout   float64 `yaml:"tracker_timeout" json:"tracker_timeout" toml:"tracker_timeout" usage:"segundos sem ver a pessoa até remover o tracking"`
+
+	// Relógio da análise comportamental
+	ClockSource string  `yaml:"clock_source" json:"clock_source" toml:"clock_source" usage:"origem do tempo: wall (relógio), frame (timestamps do vídeo) ou auto"`
+	FrameRate   float64 `yaml:"frame_rate" json:"frame_rate" toml:"frame_rate" usage:"quadros por segundo assumidos quando a fonte não informa timestamps"`
+
+	// Tracking
+	TrackerType           string  `yaml:"tracker_type" json:"tracker_type" toml:"tracker_type" usage:"algoritmo de tracking: sort ou centroid"`
+	TrackerIoUThreshold   float64 `yaml:"tracker_iou_threshold" json:"tracker_iou_threshold" toml:"tracker_iou_threshold" usage:"IoU mínimo para associar detecção e trilha (0..1)"`
+	TrackerMinHits        int     `yaml:"tracker_min_hits" json:"tracker_min_hits" toml:"tracker_min_hits" usage:"detecções consecutivas para confirmar uma trilha"`
+	TrackerHighConfidence float32 `yaml:"tracker_high_confidence" json:"tracker_high_confidence" toml:"tracker_high_confidence" usage:"confiança mínima para criar trilhas (0..1)"`
+	TrackEvictionPolicy   string  `yaml:"track_eviction_policy" json:"track_eviction_policy" toml:"track_eviction_policy" usage:"remoção ao atingir max_tracked_people: oldest ou lowest_confidence"`
 }
 
 // DefaultConfig retorna configuração padrão
@@ -35,56 +140,240 @@ func DefaultConfig() *Config {
 		ObjectDetectionModel: "models/yolo11n_object365.onnx",
 		ClassNamesFile:       "models/object365.names",
 
+		// Fonte de vídeo
+		SourceType: "auto",
+		Source:     "", // vazio = testa câmeras 0-3
+		CameraID:   "cam0",
+
 		// Thresholds de detecção
 		ConfidenceThreshold: 0.25,
 		NMSThreshold:        0.4,
+		NMSStrategy:         "global",
+		NMSSoftSigma:        0.5,
+		MaxDetections:       300,
 		MinObjectSize:       20,
 
 		// Configurações de shoplifting
-		HidingBehaviorThreshold:    0.7,
-		LoiteringTimeThreshold:     20.0, // segundos
-		ProximityThreshold:         80.0, // pixels
+		HidingBehaviorThreshold: 0.7,
+		LoiteringTimeThreshold:  20.0, // segundos
+		ProximityThreshold:      80.0, // pixels
+
+		// Pontuação de risco
+		RiskWeightLoitering: 0.05, // por segundo
+		RiskWeightProximity: 0.1,  // por segundo
+		RiskWeightMovement:  0.5,  // por evento
+		RiskWeightZone:      0.5,
+		RiskHalfLife:        10, // segundos
+		RiskThreshold:       0.7,
+
+		// Alertas
+		AlertWebhookTimeout:   5.0, // segundos
+		AlertWebhookRetries:   5,
+		AlertWebhookQueueSize: 100,
+		AlertMQTTTopic:        "poc-camera/alertas",
+		AlertMQTTClientID:     "poc-camera",
+
+		// Incidentes
+		IncidentCloseAfter:     3,
+		IncidentUpdateInterval: 5,
+
+		// Evidências
+		EvidencePreRollFrames:  45, // ~1.5s a 30fps
+		EvidencePostRollFrames: 90, // ~3s a 30fps
+		EvidenceClipFPS:        20,
+		EvidenceMaxFiles:       500,
+		EvidenceMaxSizeMB:      1024,
+		EvidenceMaxAgeHours:    168, // 7 dias
+
+		// Avaliação
+		EvalIoUThreshold: 0.5,
+
+		// Catálogo de itens valiosos
+		ValuableItems: DefaultValuableItems(),
 
 		// Interface
+		Headless:        false,
 		WindowName:      "🛡️ Shoplifting Detector - YOLO v11 Object Detection",
 		InputSize:       640,
 		NumDetections:   8400,
 		NumAttributes:   369, // 4 coordenadas + 365 classes Object365
 		MaxValidClassID: 364, // Object365: 365 classes (0-364)
+		OutputLayout:    "transposed",
+		Letterbox:       false,
+		LetterboxColor:  "114,114,114", // cinza usado no treino da Ultralytics
+		MosaicTileWidth: 640,
+
+		// Logs
+		LogFormat: "console",
+		LogLevel:  "info",
+
+		// Pipeline
+		PipelineQueueSize: 2,
+		DetectorPoolSize:  1,
+		DetectorBatchSize: 1,
+
+		// Taxa de detecção
+		DetectionStride:    1,
+		DetectionMaxStride: 5,
 
 		// Performance
 		MaxTrackedPeople: 50,
 		TrackerTimeout:   5.0, // segundos
+
+		// Relógio
+		ClockSource: "auto", // frame para arquivos e imagens, wall para câmeras e streams
+		FrameRate:   30,
+
+		// Tracking
+		TrackerType:           "sort",
+		TrackerIoUThreshold:   0.3,
+		TrackerMinHits:        3,
+		TrackerHighConfidence: 0.4,
+		TrackEvictionPolicy:   "oldest",
+	}
+}
+
+// Tipos de zona
+const (
+	ZoneShelf     = "shelf"      // prateleira/gôndola: conta tempo de permanência
+	ZoneHighValue = "high_value" // expositor de alto valor: conta tempo de permanência
+	ZoneCheckout  = "checkout"   // caixa
+	ZoneExit      = "exit"       // saída da loja
+	ZoneStaffOnly = "staff_only" // área restrita a funcionários
+	ZoneIgnore    = "ignore"     // máscara: detecções nesta região são descartadas
+)
+
+// Zone define uma região poligonal da loja em coordenadas de pixel do frame
+type Zone struct {
+	Name           string   `yaml:"name" json:"name" toml:"name"`
+	Type           string   `yaml:"type" json:"type" toml:"type"`
+	Points         [][2]int `yaml:"points" json:"points" toml:"points"`                            // vértices [x, y] do polígono
+	DwellThreshold float64  `yaml:"dwell_threshold" json:"dwell_threshold" toml:"dwell_threshold"` // segundos; 0 = loitering_time_threshold
+}
+
+// Camera define uma fonte de vídeo com suas próprias zonas
+type Camera struct {
+	ID         string `yaml:"id" json:"id" toml:"id"`                            // identificador nos alertas e na tela
+	SourceType string `yaml:"source_type" json:"source_type" toml:"source_type"` // vazio = auto
+	Source     string `yaml:"source" json:"source" toml:"source"`
+	Zones      []Zone `yaml:"zones" json:"zones" toml:"zones"`
+}
+
+// CameraList retorna as câmeras configuradas; sem a lista cameras, uma única
+// câmera com a fonte e as zonas do nível superior
+func (c *Config) CameraList() []Camera {
+	if len(c.Cameras) > 0 {
+		return c.Cameras
 	}
+	return []Camera{{ID: c.CameraID, SourceType: c.SourceType, Source: c.Source, Zones: c.Zones}}
 }
 
-// GetValuableItems define IDs de classes consideradas valiosas
-func GetValuableItems() map[int]string {
-	return map[int]string{
-		// Eletrônicos (ajustar IDs conforme arquivo de classes)
-		50:  "telefone",
-		51:  "notebook",
-		52:  "tablet",
-		53:  "câmera",
-		54:  "fone de ouvido",
+// ForCamera retorna uma cópia da configuração com a fonte e as zonas da câmera
+func (c *Config) ForCamera(cam Camera) *Config {
+	camCfg := *c
+	camCfg.SourceType = cam.SourceType
+	if camCfg.SourceType == "" {
+		camCfg.SourceType = "auto"
+	}
+	camCfg.Source = cam.Source
+	camCfg.CameraID = cam.ID
+	camCfg.Zones = cam.Zones
+	camCfg.Cameras = nil
+	return &camCfg
+}
+
+// ValuableItem define um item valioso do catálogo pelo nome da classe
+type ValuableItem struct {
+	Name     string  `yaml:"name" json:"name" toml:"name"`             // nome exatamente como no arquivo de classes
+	Category string  `yaml:"category" json:"category" toml:"category"` // agrupamento para relatórios
+	Weight   float32 `yaml:"weight" json:"weight" toml:"weight"`       // peso de valor (0..1], escala a confiança dos alertas
+}
+
+// DefaultValuableItems retorna o catálogo padrão (nomes de models/object365.names)
+func DefaultValuableItems() []ValuableItem {
+	return []ValuableItem{
+		// Eletrônicos
+		{Name: "celular", Category: "eletrônicos", Weight: 1.0},
+		{Name: "laptop", Category: "eletrônicos", Weight: 1.0},
+		{Name: "tablet", Category: "eletrônicos", Weight: 1.0},
+		{Name: "câmera", Category: "eletrônicos", Weight: 0.9},
+		{Name: "fone de ouvido", Category: "eletrônicos", Weight: 0.7},
+		{Name: "telefone", Category: "eletrônicos", Weight: 0.6},
 
 		// Acessórios
-		100: "bolsa",
-		101: "carteira",
-		102: "relógio",
-
-		// Roupas de valor
-		200: "casaco",
-		201: "tênis",
-		202: "jaqueta",
-
-		// Cosméticos/Perfumes
-		300: "perfume",
-		301: "maquiagem",
-
-		// Bebidas/Comidas premium
-		350: "vinho",
-		351: "whisky",
-		352: "chocolate premium",
+		{Name: "anel", Category: "acessórios", Weight: 1.0},
+		{Name: "colar", Category: "acessórios", Weight: 0.9},
+		{Name: "relógio", Category: "acessórios", Weight: 0.9},
+		{Name: "pulseira", Category: "acessórios", Weight: 0.8},
+		{Name: "carteira/bolsa", Category: "acessórios", Weight: 0.8},
+		{Name: "bolsa/maleta", Category: "acessórios", Weight: 0.7},
+		{Name: "óculos", Category: "acessórios", Weight: 0.5},
+
+		// Vestuário
+		{Name: "tênis", Category: "vestuário", Weight: 0.6},
+		{Name: "sapatos de couro", Category: "vestuário", Weight: 0.6},
+		{Name: "botas", Category: "vestuário", Weight: 0.6},
+		{Name: "cinto", Category: "vestuário", Weight: 0.4},
+
+		// Cosméticos
+		{Name: "cosméticos", Category: "cosméticos", Weight: 0.6},
+		{Name: "batom", Category: "cosméticos", Weight: 0.5},
+
+		// Bebidas (vinhos, destilados)
+		{Name: "garrafa", Category: "bebidas", Weight: 0.4},
 	}
-}
\ No newline at end of file
+}
+
+// ResolveValuableItems associa cada item do catálogo a todos os IDs de classe
+// de mesmo nome. Nomes duplicados no arquivo de classes (ex: "relógio" de
+// pulso e de parede) valem todos; com class_remap, o item vale para o ID
+// final da classe. Retorna erro listando todos os itens que não existem na
+// lista de classes.
+func (c *Config) ResolveValuableItems(classNames []string) (map[int]ValuableItem, error) {
+	ix, err := c.newClassIndex(classNames)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[int]ValuableItem, len(c.ValuableItems))
+	var missing []string
+	for _, item := range c.ValuableItems {
+		ids := ix.lookupAll(item.Name)
+		if len(ids) == 0 {
+			missing = append(missing, fmt.Sprintf("%q", item.Name))
+			continue
+		}
+		for _, id := range ids {
+			resolved[id] = item
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("itens valiosos não encontrados em %s: %s",
+			c.ClassNamesFile, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// normalizeClassName ignora maiúsculas e espaços nas pontas ao comparar nomes
+func normalizeClassName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// LetterboxRGB interpreta LetterboxColor ("R,G,B", componentes de 0 a 255)
+func (c *Config) LetterboxRGB() (color.RGBA, error) {
+	parts := strings.Split(c.LetterboxColor, ",")
+	if len(parts) != 3 {
+		return color.RGBA{}, fmt.Errorf("cor deve ter o formato R,G,B (atual: %q)", c.LetterboxColor)
+	}
+
+	var rgb [3]uint8
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > 255 {
+			return color.RGBA{}, fmt.Errorf("componente de cor inválido %q (use 0 a 255)", part)
+		}
+		rgb[i] = uint8(n)
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}, nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// resolvedIDs retorna os IDs de classe de cada item resolvido, por nome
+func resolvedIDs(resolved map[int]ValuableItem) map[string][]int {
+	ids := make(map[string][]int)
+	for id, item := range resolved {
+		ids[item.Name] = append(ids[item.Name], id)
+	}
+	for _, list := range ids {
+		sort.Ints(list)
+	}
+	return ids
+}
+
+func TestResolveValuableItems(t *testing.T) {
+	classNames := []string{"pessoa", "relógio", "celular", "Relógio ", "tênis", "mochila", "tênis"}
+
+	tests := []struct {
+		name  string
+		items []ValuableItem
+		remap []ClassRemap
+		want  map[string][]int
+	}{
+		{
+			name:  "nome único",
+			items: []ValuableItem{{Name: "celular"}},
+			want:  map[string][]int{"celular": {2}},
+		},
+		{
+			name:  "nomes repetidos valem todos os IDs",
+			items: []ValuableItem{{Name: "relógio"}, {Name: "tênis"}},
+			want:  map[string][]int{"relógio": {1, 3}, "tênis": {4, 6}},
+		},
+		{
+			name:  "maiúsculas e espaços ignorados",
+			items: []ValuableItem{{Name: " CELULAR"}},
+			want:  map[string][]int{" CELULAR": {2}},
+		},
+		{
+			// Os dois "tênis" passam a ser "mochila": o item vale para o ID final
+			name:  "classe remapeada para existente",
+			items: []ValuableItem{{Name: "tênis"}},
+			remap: []ClassRemap{{From: []string{"tênis"}, To: "mochila"}},
+			want:  map[string][]int{"tênis": {5}},
+		},
+		{
+			// "relógio" vira o rótulo novo "acessório" no primeiro ID
+			name:  "rótulo novo do remapeamento",
+			items: []ValuableItem{{Name: "acessório"}, {Name: "relógio"}},
+			remap: []ClassRemap{{From: []string{"relógio"}, To: "acessório"}},
+			want:  map[string][]int{"relógio": {1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ValuableItems = tt.items
+			cfg.ClassRemap = tt.remap
+
+			resolved, err := cfg.ResolveValuableItems(classNames)
+			if err != nil {
+				t.Fatalf("ResolveValuableItems: %v", err)
+			}
+			if got := resolvedIDs(resolved); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IDs = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValuableItemsMissing(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ClassNamesFile = "classes.names"
+	cfg.ValuableItems = []ValuableItem{{Name: "celular"}, {Name: "drone"}, {Name: "perfume"}}
+
+	_, err := cfg.ResolveValuableItems([]string{"pessoa", "celular"})
+	if err == nil {
+		t.Fatal("esperado erro para itens inexistentes")
+	}
+	for _, want := range []string{"classes.names", `"drone"`, `"perfume"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("erro %q não menciona %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "celular") {
+		t.Errorf("erro %q menciona item encontrado", err)
+	}
+}
+
+func TestDefaultValuableItemsResolveDuplicates(t *testing.T) {
+	data, err := os.ReadFile("../models/object365.names")
+	if err != nil {
+		t.Fatalf("erro ao ler classes: %v", err)
+	}
+	classNames := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	cfg := DefaultConfig()
+	resolved, err := cfg.ResolveValuableItems(classNames)
+	if err != nil {
+		t.Fatalf("catálogo padrão: %v", err)
+	}
+
+	// Linhas repetidas em object365.names (IDs começam em 0)
+	ids := resolvedIDs(resolved)
+	for name, want := range map[string][]int{
+		"fone de ouvido": {125, 207},
+		"relógio":        {42, 94},
+		"tênis":          {1, 210},
+	} {
+		if !reflect.DeepEqual(ids[name], want) {
+			t.Errorf("%s: IDs = %v, esperado %v", name, ids[name], want)
+		}
+	}
+}
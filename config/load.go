@@ -0,0 +1,246 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix é o prefixo das variáveis de ambiente que sobrescrevem a configuração
+const EnvPrefix = "POC_CAMERA_"
+
+// configFileEnv indica o arquivo de configuração quando -config não é informado
+const configFileEnv = EnvPrefix + "CONFIG"
+
+// Load monta a configuração em camadas: padrões, arquivo (-config ou
+// POC_CAMERA_CONFIG), variáveis de ambiente e, por último, flags da linha
+// de comando. O resultado é validado antes de ser retornado.
+func Load(args []string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	fs := flag.NewFlagSet("poc-camera", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(configFileEnv), "arquivo de configuração (.yaml, .yml, .json ou .toml)")
+	overrides := registerFlags(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := LoadFile(cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	// Flags têm prioridade sobre arquivo e ambiente
+	for _, o := range overrides {
+		if !o.set {
+			continue
+		}
+		if err := setField(fieldByKey(cfg, o.key), o.value); err != nil {
+			return nil, fmt.Errorf("flag -%s: %v", o.name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFile sobrepõe em cfg os valores do arquivo, escolhendo o formato pela extensão.
+// Listas presentes no arquivo substituem as de cfg por inteiro.
+func LoadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo de configuração: %v", err)
+	}
+
+	var unmarshal func([]byte, any) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".json":
+		unmarshal = json.Unmarshal
+	case ".toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return fmt.Errorf("formato de configuração não suportado: %s", path)
+	}
+
+	// JSON e TOML decodificam sobre os elementos já existentes das listas, e
+	// um item parcial herdaria os campos do item padrão na mesma posição.
+	// Uma primeira leitura descobre as listas definidas no arquivo, que são
+	// zeradas antes da leitura definitiva.
+	var fromFile Config
+	if err := unmarshal(data, &fromFile); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %v", path, err)
+	}
+	resetListsSetIn(cfg, &fromFile)
+
+	if err := unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %v", path, err)
+	}
+	return nil
+}
+
+// resetListsSetIn zera em cfg os campos de lista definidos em fromFile
+func resetListsSetIn(cfg, fromFile *Config) {
+	dst := reflect.ValueOf(cfg).Elem()
+	src := reflect.ValueOf(fromFile).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		if dst.Field(i).Kind() == reflect.Slice && !src.Field(i).IsNil() {
+			dst.Field(i).Set(reflect.Zero(dst.Field(i).Type()))
+		}
+	}
+}
+
+// flagOverride guarda o valor de uma flag até as outras camadas serem aplicadas
+type flagOverride struct {
+	key   string
+	name  string
+	value string
+	set   bool
+}
+
+// String implementa flag.Value
+func (o *flagOverride) String() string {
+	return o.value
+}
+
+// Set implementa flag.Value
+func (o *flagOverride) Set(value string) error {
+	o.value = value
+	o.set = true
+	return nil
+}
+
+// boolFlagOverride permite usar flags booleanas sem valor (ex: -headless)
+type boolFlagOverride struct {
+	*flagOverride
+}
+
+// IsBoolFlag implementa a interface opcional de flag para booleanos
+func (boolFlagOverride) IsBoolFlag() bool {
+	return true
+}
+
+// registerFlags cria uma flag para cada campo escalar da configuração
+func registerFlags(fs *flag.FlagSet, cfg *Config) []*flagOverride {
+	var overrides []*flagOverride
+
+	forEachScalarField(cfg, func(key string, field reflect.StructField, value reflect.Value) {
+		o := &flagOverride{
+			key:   key,
+			name:  strings.ReplaceAll(key, "_", "-"),
+			value: fmt.Sprint(value.Interface()),
+		}
+		overrides = append(overrides, o)
+
+		if value.Kind() == reflect.Bool {
+			fs.Var(boolFlagOverride{o}, o.name, field.Tag.Get("usage"))
+		} else {
+			fs.Var(o, o.name, field.Tag.Get("usage"))
+		}
+	})
+
+	return overrides
+}
+
+// applyEnv aplica variáveis POC_CAMERA_<CHAVE> definidas no ambiente
+func applyEnv(cfg *Config) error {
+	var firstErr error
+
+	forEachScalarField(cfg, func(key string, _ reflect.StructField, value reflect.Value) {
+		envName := EnvPrefix + strings.ToUpper(key)
+		raw, ok := os.LookupEnv(envName)
+		if !ok || firstErr != nil {
+			return
+		}
+		if err := setField(value, raw); err != nil {
+			firstErr = fmt.Errorf("variável %s: %v", envName, err)
+		}
+	})
+
+	return firstErr
+}
+
+// forEachScalarField percorre os campos escalares (string, bool, números) da configuração
+func forEachScalarField(cfg *Config, fn func(key string, field reflect.StructField, value reflect.Value)) {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := fieldKey(field)
+		if key == "" {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Float32, reflect.Float64:
+			fn(key, field, v.Field(i))
+		}
+	}
+}
+
+// fieldKey retorna a chave de configuração de um campo (tag yaml)
+func fieldKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+// fieldByKey localiza o campo da configuração pela chave
+func fieldByKey(cfg *Config, key string) reflect.Value {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if fieldKey(t.Field(i)) == key {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// setField converte o texto para o tipo do campo e o atribui
+func setField(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("booleano inválido: %q", raw)
+		}
+		value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("inteiro inválido: %q", raw)
+		}
+		value.SetInt(int64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("número inválido: %q", raw)
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("tipo não suportado: %s", value.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig grava um arquivo de configuração temporário
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileReplacesLists(t *testing.T) {
+	// Item parcial: não pode herdar categoria e peso do primeiro item padrão
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `valuable_items:
+  - name: garrafa
+  - name: relógio
+    category: acessórios
+    weight: 0.5
+`,
+		},
+		{
+			name:    "json",
+			file:    "config.json",
+			content: `{"valuable_items": [{"name": "garrafa"}, {"name": "relógio", "category": "acessórios", "weight": 0.5}]}`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `[[valuable_items]]
+name = "garrafa"
+
+[[valuable_items]]
+name = "relógio"
+category = "acessórios"
+weight = 0.5
+`,
+		},
+	}
+
+	want := []ValuableItem{
+		{Name: "garrafa"},
+		{Name: "relógio", Category: "acessórios", Weight: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if err := LoadFile(cfg, writeConfig(t, tt.file, tt.content)); err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.ValuableItems, want) {
+				t.Errorf("valuable_items = %+v, esperado %+v", cfg.ValuableItems, want)
+			}
+		})
+	}
+}
+
+func TestLoadFileKeepsUnsetLists(t *testing.T) {
+	for _, tt := range []struct{ file, content string }{
+		{"config.yaml", "confidence_threshold: 0.4\n"},
+		{"config.json", `{"confidence_threshold": 0.4}`},
+		{"config.toml", "confidence_threshold = 0.4\n"},
+	} {
+		t.Run(filepath.Ext(tt.file), func(t *testing.T) {
+			cfg := DefaultConfig()
+			if err := LoadFile(cfg, writeConfig(t, tt.file, tt.content)); err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if cfg.ConfidenceThreshold != 0.4 {
+				t.Errorf("confidence_threshold = %v, esperado 0.4", cfg.ConfidenceThreshold)
+			}
+			if !reflect.DeepEqual(cfg.ValuableItems, DefaultValuableItems()) {
+				t.Errorf("catálogo padrão alterado sem valuable_items no arquivo: %+v", cfg.ValuableItems)
+			}
+		})
+	}
+}
+
+func TestLoadFileUnsupportedFormat(t *testing.T) {
+	if err := LoadFile(DefaultConfig(), writeConfig(t, "config.ini", "x=1")); err == nil {
+		t.Error("esperado erro para formato desconhecido")
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := "confidence_threshold: 0.3\ntracker_type: centroid\nheadless: false\n"
+
+	tests := []struct {
+		name           string
+		file           bool // passa o arquivo por -config
+		fileEnv        bool // passa o arquivo por POC_CAMERA_CONFIG
+		env            map[string]string
+		args           []string
+		wantConfidence float32
+		wantTracker    string
+		wantHeadless   bool
+	}{
+		{
+			name:           "padrões",
+			wantConfidence: DefaultConfig().ConfidenceThreshold,
+			wantTracker:    DefaultConfig().TrackerType,
+		},
+		{
+			name:           "arquivo sobre padrões",
+			file:           true,
+			wantConfidence: 0.3,
+			wantTracker:    "centroid",
+		},
+		{
+			name:           "arquivo por variável de ambiente",
+			fileEnv:        true,
+			wantConfidence: 0.3,
+			wantTracker:    "centroid",
+		},
+		{
+			name:           "ambiente sobre arquivo",
+			file:           true,
+			env:            map[string]string{"POC_CAMERA_CONFIDENCE_THRESHOLD": "0.35", "POC_CAMERA_HEADLESS": "true"},
+			wantConfidence: 0.35,
+			wantTracker:    "centroid",
+			wantHeadless:   true,
+		},
+		{
+			name:           "flags sobre ambiente e arquivo",
+			file:           true,
+			env:            map[string]string{"POC_CAMERA_CONFIDENCE_THRESHOLD": "0.35", "POC_CAMERA_TRACKER_TYPE": "sort"},
+			args:           []string{"-confidence-threshold", "0.4", "-headless"},
+			wantConfidence: 0.4,
+			wantTracker:    "sort",
+			wantHeadless:   true,
+		},
+		{
+			name:           "flag booleana explícita vence o ambiente",
+			env:            map[string]string{"POC_CAMERA_HEADLESS": "true"},
+			args:           []string{"-headless=false"},
+			wantConfidence: DefaultConfig().ConfidenceThreshold,
+			wantTracker:    DefaultConfig().TrackerType,
+			wantHeadless:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, "config.yaml", file)
+			t.Setenv(configFileEnv, "")
+			if tt.fileEnv {
+				t.Setenv(configFileEnv, path)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if tt.file {
+				args = append([]string{"-config", path}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.ConfidenceThreshold != tt.wantConfidence {
+				t.Errorf("confidence_threshold = %v, esperado %v", cfg.ConfidenceThreshold, tt.wantConfidence)
+			}
+			if cfg.TrackerType != tt.wantTracker {
+				t.Errorf("tracker_type = %q, esperado %q", cfg.TrackerType, tt.wantTracker)
+			}
+			if cfg.Headless != tt.wantHeadless {
+				t.Errorf("headless = %v, esperado %v", cfg.Headless, tt.wantHeadless)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string // trecho esperado na mensagem
+	}{
+		{name: "variável inválida", env: map[string]string{"POC_CAMERA_INPUT_SIZE": "grande"}, want: "POC_CAMERA_INPUT_SIZE"},
+		{name: "flag inválida", args: []string{"-headless=talvez"}, want: "headless"},
+		{name: "arquivo inexistente", args: []string{"-config", "/nao/existe.yaml"}, want: "erro ao ler"},
+		{name: "valor fora da faixa", args: []string{"-nms-threshold", "1.5"}, want: "nms_threshold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configFileEnv, "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := Load(tt.args)
+			if err == nil {
+				t.Fatal("esperado erro")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erro %q não menciona %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorFields(t *testing.T) {
+	triangle := [][2]int{{0, 0}, {10, 0}, {0, 10}}
+
+	tests := []struct {
+		name      string
+		configure func(*Config)
+		want      []string
+	}{
+		{
+			name:      "campo escalar",
+			configure: func(c *Config) { c.NMSThreshold = 1.5 },
+			want:      []string{"nms_threshold"},
+		},
+		{
+			name: "vários campos na ordem de validação",
+			configure: func(c *Config) {
+				c.ConfidenceThreshold = -1
+				c.LogLevel = "verbose"
+				c.InputSize = 100
+			},
+			want: []string{"confidence_threshold", "input_size", "log_level"},
+		},
+		{
+			name: "zonas",
+			configure: func(c *Config) {
+				c.Zones = []Zone{
+					{Name: "caixa", Type: ZoneCheckout, Points: triangle},
+					{Name: "caixa", Type: "porta", Points: triangle[:2]},
+				}
+			},
+			want: []string{"zones[1].name", "zones[1].type", "zones[1].points"},
+		},
+		{
+			name: "zonas por câmera",
+			configure: func(c *Config) {
+				c.Cameras = []Camera{
+					{ID: "entrada", Zones: []Zone{{Name: "saída", Type: ZoneExit, Points: triangle, DwellThreshold: -1}}},
+					{ID: "entrada", SourceType: "ftp"},
+				}
+			},
+			want: []string{"cameras[0].zones[0].dwell_threshold", "cameras[1].id", "cameras[1].source_type"},
+		},
+		{
+			name: "listas de classes e itens",
+			configure: func(c *Config) {
+				c.ClassOverrides = []ClassOverride{{Name: "celular", ConfidenceThreshold: 2}}
+				c.ClassRemap = []ClassRemap{{To: "calçados"}}
+				c.ValuableItems = []ValuableItem{{Name: "celular", Weight: 1}, {Name: "garrafa"}}
+			},
+			want: []string{
+				"class_overrides[0].confidence_threshold",
+				"class_remap[0].from",
+				"valuable_items[1].weight",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.configure(cfg)
+
+			err := cfg.Validate()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("erro = %v, esperado *ValidationError", err)
+			}
+			got := make([]string, len(verr.Fields))
+			for i, f := range verr.Fields {
+				got[i] = f.Field
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("campos = %v, esperado %v", got, tt.want)
+			}
+			if !strings.HasPrefix(err.Error(), "configuração inválida: "+tt.want[0]+": ") {
+				t.Errorf("mensagem = %q, esperado começar pelo primeiro campo", err)
+			}
+		})
+	}
+}
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("configuração padrão inválida: %v", err)
+	}
+}
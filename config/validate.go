@@ -0,0 +1,388 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// validSourceTypes lista os tipos de fonte aceitos (ver internal/source)
+var validSourceTypes = map[string]bool{
+	"auto":   true,
+	"device": true,
+	"file":   true,
+	"stream": true,
+	"images": true,
+}
+
+// validTrackerTypes lista os algoritmos de tracking aceitos (ver internal/tracking)
+var validTrackerTypes = map[string]bool{
+	"sort":     true,
+	"centroid": true,
+}
+
+// validEvictionPolicies lista as políticas de remoção de trilhas aceitas
+var validEvictionPolicies = map[string]bool{
+	"oldest":            true,
+	"lowest_confidence": true,
+}
+
+// validClockSources lista as origens de tempo aceitas
+var validClockSources = map[string]bool{
+	"auto":  true,
+	"wall":  true,
+	"frame": true,
+}
+
+// validNMSStrategies lista as estratégias de NMS aceitas
+var validNMSStrategies = map[string]bool{
+	"class_aware": true,
+	"global":      true,
+	"soft":        true,
+}
+
+// validLogFormats lista os formatos de log aceitos (ver internal/logging)
+var validLogFormats = map[string]bool{
+	"console": true,
+	"text":    true,
+	"json":    true,
+}
+
+// validLogLevels lista os níveis de log aceitos
+var validLogLevels = map[string]bool{
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+}
+
+// validOutputLayouts lista os layouts de saída do modelo aceitos
+var validOutputLayouts = map[string]bool{
+	"transposed": true,
+	"row_major":  true,
+	"objectness": true,
+	"end_to_end": true,
+}
+
+// validZoneTypes lista os tipos de zona aceitos
+var validZoneTypes = map[string]bool{
+	ZoneShelf:     true,
+	ZoneHighValue: true,
+	ZoneCheckout:  true,
+	ZoneExit:      true,
+	ZoneStaffOnly: true,
+	ZoneIgnore:    true,
+}
+
+// FieldError descreve um campo de configuração inválido
+type FieldError struct {
+	Field   string // chave de configuração (ex: nms_threshold)
+	Message string
+}
+
+// Error implementa a interface error
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError agrupa todos os campos inválidos encontrados
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implementa a interface error
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "configuração inválida: " + strings.Join(msgs, "; ")
+}
+
+// validator acumula erros de campo
+type validator struct {
+	fields []FieldError
+}
+
+// fail registra um campo inválido
+func (v *validator) fail(field, format string, args ...any) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// unitRange exige valor entre 0 e 1
+func (v *validator) unitRange(field string, value float32) {
+	if value < 0 || value > 1 {
+		v.fail(field, "deve estar entre 0 e 1 (atual: %g)", value)
+	}
+}
+
+// positive exige valor maior que zero
+func (v *validator) positive(field string, value float64) {
+	if value <= 0 {
+		v.fail(field, "deve ser maior que zero (atual: %g)", value)
+	}
+}
+
+// nonNegative exige inteiro maior ou igual a zero
+func (v *validator) nonNegative(field string, value int) {
+	if value < 0 {
+		v.fail(field, "não pode ser negativo (atual: %d)", value)
+	}
+}
+
+// zones valida uma lista de zonas (nomes únicos, tipo, polígono)
+func (v *validator) zones(prefix string, zones []Zone) {
+	zoneNames := make(map[string]bool, len(zones))
+	for i, zone := range zones {
+		field := fmt.Sprintf("%s[%d]", prefix, i)
+		if zone.Name == "" {
+			v.fail(field+".name", "não pode ser vazio")
+		} else if zoneNames[zone.Name] {
+			v.fail(field+".name", "nome repetido %q", zone.Name)
+		}
+		zoneNames[zone.Name] = true
+		if !validZoneTypes[zone.Type] {
+			v.fail(field+".type", "tipo desconhecido %q (use shelf, high_value, checkout, exit, staff_only ou ignore)", zone.Type)
+		}
+		if len(zone.Points) < 3 {
+			v.fail(field+".points", "o polígono precisa de pelo menos 3 vértices (atual: %d)", len(zone.Points))
+		}
+		if zone.DwellThreshold < 0 {
+			v.fail(field+".dwell_threshold", "não pode ser negativo (atual: %g)", zone.DwellThreshold)
+		}
+	}
+}
+
+// err retorna o erro agregado ou nil
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate verifica a consistência dos valores e informa quais campos são inválidos
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.ObjectDetectionModel == "" {
+		v.fail("object_detection_model", "não pode ser vazio")
+	}
+	if c.ClassNamesFile == "" {
+		v.fail("class_names_file", "não pode ser vazio")
+	}
+	if !validSourceTypes[c.SourceType] {
+		v.fail("source_type", "tipo desconhecido %q (use auto, device, file, stream ou images)", c.SourceType)
+	}
+
+	v.unitRange("confidence_threshold", c.ConfidenceThreshold)
+	v.unitRange("nms_threshold", c.NMSThreshold)
+	if !validNMSStrategies[c.NMSStrategy] {
+		v.fail("nms_strategy", "estratégia desconhecida %q (use global, class_aware ou soft)", c.NMSStrategy)
+	} else if c.NMSStrategy == "soft" {
+		v.positive("nms_soft_sigma", c.NMSSoftSigma)
+	}
+	v.nonNegative("max_detections", c.MaxDetections)
+	v.unitRange("hiding_behavior_threshold", c.HidingBehaviorThreshold)
+	v.nonNegative("min_object_size", c.MinObjectSize)
+
+	v.positive("loitering_time_threshold", c.LoiteringTimeThreshold)
+	v.positive("proximity_threshold", c.ProximityThreshold)
+
+	riskWeights := []struct {
+		field string
+		value float64
+	}{
+		{"risk_weight_loitering", c.RiskWeightLoitering},
+		{"risk_weight_proximity", c.RiskWeightProximity},
+		{"risk_weight_movement", c.RiskWeightMovement},
+		{"risk_weight_zone", c.RiskWeightZone},
+	}
+	for _, w := range riskWeights {
+		if w.value < 0 {
+			v.fail(w.field, "não pode ser negativo (atual: %g)", w.value)
+		}
+	}
+	if c.RiskHalfLife < 0 {
+		v.fail("risk_half_life", "não pode ser negativo (atual: %g)", c.RiskHalfLife)
+	}
+	if c.RiskThreshold < 0 || c.RiskThreshold > 1 {
+		v.fail("risk_threshold", "deve estar entre 0 e 1 (atual: %g)", c.RiskThreshold)
+	}
+
+	if c.InputSize <= 0 || c.InputSize%32 != 0 {
+		v.fail("input_size", "deve ser múltiplo positivo de 32 (atual: %d)", c.InputSize)
+	}
+	v.positive("num_detections", float64(c.NumDetections))
+	v.positive("pipeline_queue_size", float64(c.PipelineQueueSize))
+	v.positive("detector_pool_size", float64(c.DetectorPoolSize))
+	v.positive("detector_batch_size", float64(c.DetectorBatchSize))
+	v.positive("detection_stride", float64(c.DetectionStride))
+	if c.DetectionTargetLatency < 0 {
+		v.fail("detection_target_latency", "não pode ser negativo (atual: %g)", c.DetectionTargetLatency)
+	} else if c.DetectionTargetLatency > 0 && c.DetectionMaxStride < c.DetectionStride {
+		v.fail("detection_max_stride", "deve ser pelo menos detection_stride (%d, atual: %d)", c.DetectionStride, c.DetectionMaxStride)
+	}
+	if _, err := c.LetterboxRGB(); err != nil {
+		v.fail("letterbox_color", "%v", err)
+	}
+	if !validOutputLayouts[c.OutputLayout] {
+		v.fail("output_layout", "layout desconhecido %q (use transposed, row_major, objectness ou end_to_end)", c.OutputLayout)
+	} else if c.OutputLayout == "end_to_end" {
+		if c.NumAttributes != 6 {
+			v.fail("num_attributes", "deve ser 6 no layout end_to_end (atual: %d)", c.NumAttributes)
+		}
+		v.nonNegative("max_valid_class_id", c.MaxValidClassID)
+	} else if numClasses := c.NumAttributes - c.boxAttributes(); numClasses <= 0 {
+		v.fail("num_attributes", "deve ser maior que %d (coordenadas + classes, atual: %d)", c.boxAttributes(), c.NumAttributes)
+	} else if c.MaxValidClassID < 0 || c.MaxValidClassID >= numClasses {
+		v.fail("max_valid_class_id", "deve estar entre 0 e %d (classes - 1, atual: %d)", numClasses-1, c.MaxValidClassID)
+	}
+
+	if c.AlertWebhookURL != "" {
+		if u, err := url.Parse(c.AlertWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			v.fail("alert_webhook_url", "URL HTTP(S) inválida: %q", c.AlertWebhookURL)
+		}
+		v.positive("alert_webhook_timeout", c.AlertWebhookTimeout)
+		v.positive("alert_webhook_queue_size", float64(c.AlertWebhookQueueSize))
+		v.nonNegative("alert_webhook_retries", c.AlertWebhookRetries)
+	}
+	if c.AlertMQTTBroker != "" && c.AlertMQTTTopic == "" {
+		v.fail("alert_mqtt_topic", "obrigatório quando alert_mqtt_broker está definido")
+	}
+
+	v.positive("incident_close_after", c.IncidentCloseAfter)
+	if c.IncidentUpdateInterval < 0 {
+		v.fail("incident_update_interval", "não pode ser negativo (atual: %g)", c.IncidentUpdateInterval)
+	}
+
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			v.fail("metrics_addr", "endereço inválido (esperado host:porta ou :porta): %q", c.MetricsAddr)
+		}
+	}
+
+	if c.EvidenceDir != "" {
+		v.nonNegative("evidence_pre_roll_frames", c.EvidencePreRollFrames)
+		v.nonNegative("evidence_post_roll_frames", c.EvidencePostRollFrames)
+		v.nonNegative("evidence_max_files", c.EvidenceMaxFiles)
+		v.nonNegative("evidence_max_size_mb", c.EvidenceMaxSizeMB)
+		v.positive("evidence_clip_fps", c.EvidenceClipFPS)
+		if c.EvidenceMaxAgeHours < 0 {
+			v.fail("evidence_max_age_hours", "não pode ser negativo (atual: %g)", c.EvidenceMaxAgeHours)
+		}
+	}
+
+	if c.EvalAnnotations != "" && (c.EvalIoUThreshold <= 0 || c.EvalIoUThreshold > 1) {
+		v.fail("eval_iou_threshold", "deve estar em (0, 1] (atual: %g)", c.EvalIoUThreshold)
+	}
+
+	v.zones("zones", c.Zones)
+
+	cameraIDs := make(map[string]bool, len(c.Cameras))
+	for i, cam := range c.Cameras {
+		field := fmt.Sprintf("cameras[%d]", i)
+		if cam.ID == "" {
+			v.fail(field+".id", "não pode ser vazio")
+		} else if cameraIDs[cam.ID] {
+			v.fail(field+".id", "ID repetido %q", cam.ID)
+		}
+		cameraIDs[cam.ID] = true
+		if cam.SourceType != "" && !validSourceTypes[cam.SourceType] {
+			v.fail(field+".source_type", "tipo desconhecido %q (use auto, device, file, stream ou images)", cam.SourceType)
+		}
+		v.zones(field+".zones", cam.Zones)
+	}
+	if len(c.Cameras) == 0 && c.CameraID == "" {
+		v.fail("camera_id", "não pode ser vazio")
+	}
+	if c.Mosaic {
+		v.positive("mosaic_tile_width", float64(c.MosaicTileWidth))
+		v.nonNegative("mosaic_columns", c.MosaicColumns)
+	}
+
+	for i, o := range c.ClassOverrides {
+		field := fmt.Sprintf("class_overrides[%d]", i)
+		if strings.TrimSpace(o.Name) == "" {
+			v.fail(field+".name", "não pode ser vazio")
+		}
+		if o.ConfidenceThreshold < 0 || o.ConfidenceThreshold > 1 {
+			v.fail(field+".confidence_threshold", "deve estar entre 0 e 1 (atual: %g)", o.ConfidenceThreshold)
+		}
+		v.nonNegative(field+".min_object_size", o.MinObjectSize)
+	}
+	for i, r := range c.ClassRemap {
+		field := fmt.Sprintf("class_remap[%d]", i)
+		if len(r.From) == 0 {
+			v.fail(field+".from", "precisa de pelo menos uma classe")
+		}
+		if strings.TrimSpace(r.To) == "" {
+			v.fail(field+".to", "não pode ser vazio")
+		}
+	}
+
+	for i, item := range c.ValuableItems {
+		if strings.TrimSpace(item.Name) == "" {
+			v.fail(fmt.Sprintf("valuable_items[%d].name", i), "não pode ser vazio")
+		}
+		if item.Weight <= 0 || item.Weight > 1 {
+			v.fail(fmt.Sprintf("valuable_items[%d].weight", i), "deve estar em (0, 1] (atual: %g)", item.Weight)
+		}
+	}
+
+	if !validLogFormats[c.LogFormat] {
+		v.fail("log_format", "formato desconhecido %q (use console, text ou json)", c.LogFormat)
+	}
+	if !validLogLevels[c.LogLevel] {
+		v.fail("log_level", "nível desconhecido %q (use debug, info, warn ou error)", c.LogLevel)
+	}
+
+	if !validClockSources[c.ClockSource] {
+		v.fail("clock_source", "origem desconhecida %q (use wall, frame ou auto)", c.ClockSource)
+	}
+	v.positive("frame_rate", c.FrameRate)
+
+	v.positive("max_tracked_people", float64(c.MaxTrackedPeople))
+	v.positive("tracker_timeout", c.TrackerTimeout)
+	if !validTrackerTypes[c.TrackerType] {
+		v.fail("tracker_type", "tipo desconhecido %q (use sort ou centroid)", c.TrackerType)
+	}
+	if c.TrackerIoUThreshold < 0 || c.TrackerIoUThreshold > 1 {
+		v.fail("tracker_iou_threshold", "deve estar entre 0 e 1 (atual: %g)", c.TrackerIoUThreshold)
+	}
+	v.positive("tracker_min_hits", float64(c.TrackerMinHits))
+	v.unitRange("tracker_high_confidence", c.TrackerHighConfidence)
+	if !validEvictionPolicies[c.TrackEvictionPolicy] {
+		v.fail("track_eviction_policy", "política desconhecida %q (use oldest ou lowest_confidence)", c.TrackEvictionPolicy)
+	}
+
+	return v.err()
+}
+
+// ValidateClassNames verifica se a configuração do modelo é consistente com o arquivo de classes
+func (c *Config) ValidateClassNames(classNames []string) error {
+	v := &validator{}
+
+	if c.OutputLayout != "end_to_end" {
+		if expected := c.boxAttributes() + len(classNames); c.NumAttributes != expected {
+			v.fail("num_attributes", "%s tem %d classes, esperado %d (%d + classes), atual: %d",
+				c.ClassNamesFile, len(classNames), expected, c.boxAttributes(), c.NumAttributes)
+		}
+	}
+	if c.MaxValidClassID >= len(classNames) {
+		v.fail("max_valid_class_id", "%s tem apenas %d classes (máximo %d, atual: %d)",
+			c.ClassNamesFile, len(classNames), len(classNames)-1, c.MaxValidClassID)
+	}
+
+	return v.err()
+}
+
+// boxAttributes retorna quantos atributos precedem os scores das classes
+// (coordenadas da caixa e, no layout objectness, o objectness)
+func (c *Config) boxAttributes() int {
+	if c.OutputLayout == "objectness" {
+		return 5
+	}
+	return 4
+}
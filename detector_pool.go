@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/config"
+	"poc-camera/internal/metrics"
+	"poc-camera/internal/shoplifting"
+)
+
+// detectRequest é uma imagem aguardando inferência no pool
+type detectRequest struct {
+	img   gocv.Mat
+	reply chan []shoplifting.DetectionResult
+}
+
+// YOLODetectorPool atende chamadas de Detect de várias goroutines com K
+// instâncias da rede (a gocv.Net não aceita Forward concorrente). Cada
+// instância é usada por um único worker; quando várias câmeras pedem
+// inferência ao mesmo tempo, o worker junta até batchSize imagens em um blob.
+type YOLODetectorPool struct {
+	detectors []*YOLODetector
+	requests  chan detectRequest
+	batchSize int
+	metrics   *metrics.Metrics
+	wg        sync.WaitGroup
+}
+
+// NewYOLODetectorPool carrega cfg.DetectorPoolSize instâncias da rede e
+// inicia um worker para cada uma. A duração das inferências vai para stats.
+func NewYOLODetectorPool(cfg *config.Config, stats *metrics.Metrics) (*YOLODetectorPool, error) {
+	pool := &YOLODetectorPool{
+		requests:  make(chan detectRequest),
+		batchSize: cfg.DetectorBatchSize,
+		metrics:   stats,
+	}
+	for i := 0; i < cfg.DetectorPoolSize; i++ {
+		detector, err := NewYOLODetector(cfg)
+		if err != nil {
+			for _, d := range pool.detectors {
+				d.Close()
+			}
+			return nil, fmt.Errorf("erro ao carregar instância %d da rede: %v", i+1, err)
+		}
+		pool.detectors = append(pool.detectors, detector)
+	}
+
+	// Um modelo de batch fixo devolveria saídas vazias para todo lote
+	// maior que 1: falha na inicialização em vez de perder detecções
+	if cfg.DetectorBatchSize > 1 {
+		if err := pool.detectors[0].CheckBatch(cfg.DetectorBatchSize); err != nil {
+			for _, d := range pool.detectors {
+				d.Close()
+			}
+			return nil, err
+		}
+	}
+
+	for _, detector := range pool.detectors {
+		pool.wg.Add(1)
+		go pool.worker(detector)
+	}
+	return pool, nil
+}
+
+// Detect implementa a interface shoplifting.ObjectDetector, bloqueando até
+// um worker processar a imagem
+func (p *YOLODetectorPool) Detect(img gocv.Mat) []shoplifting.DetectionResult {
+	reply := make(chan []shoplifting.DetectionResult, 1)
+	p.requests <- detectRequest{img: img, reply: reply}
+	return <-reply
+}
+
+// Labels retorna os rótulos das classes após o class_remap, indexados pelo ID
+func (p *YOLODetectorPool) Labels() []string {
+	return p.detectors[0].Labels()
+}
+
+// ClassNames retorna os nomes das classes do modelo, indexados pelo ID
+func (p *YOLODetectorPool) ClassNames() []string {
+	return p.detectors[0].ClassNames()
+}
+
+// Size retorna quantas instâncias da rede o pool mantém
+func (p *YOLODetectorPool) Size() int {
+	return len(p.detectors)
+}
+
+// Close encerra os workers e libera as redes. Não deve haver chamadas de
+// Detect em andamento.
+func (p *YOLODetectorPool) Close() {
+	close(p.requests)
+	p.wg.Wait()
+	for _, d := range p.detectors {
+		d.Close()
+	}
+}
+
+// worker processa pedidos com uma instância da rede, juntando em lote os
+// pedidos que já estiverem aguardando
+func (p *YOLODetectorPool) worker(detector *YOLODetector) {
+	defer p.wg.Done()
+
+	for req := range p.requests {
+		batch := p.collect(req)
+
+		imgs := make([]gocv.Mat, len(batch))
+		for i, r := range batch {
+			imgs[i] = r.img
+		}
+		start := time.Now()
+		results := detector.DetectBatch(imgs)
+		p.metrics.ObserveInference(time.Since(start))
+		for i, r := range batch {
+			r.reply <- toShopliftingResults(results[i])
+		}
+	}
+}
+
+// collect junta ao primeiro pedido os que estão aguardando, sem esperar
+// por novos, até batchSize
+func (p *YOLODetectorPool) collect(first detectRequest) []detectRequest {
+	batch := []detectRequest{first}
+	for len(batch) < p.batchSize {
+		select {
+		case req, ok := <-p.requests:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, req)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
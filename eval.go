@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gocv.io/x/gocv"
+	"poc-camera/internal/evaluation"
+	"poc-camera/internal/logging"
+	"poc-camera/internal/shoplifting"
+)
+
+// runEvaluation reproduz a fonte pelo pipeline completo e compara detecções,
+// trilhas e alertas com as anotações ground-truth
+func runEvaluation() {
+	groundTruth, err := evaluation.LoadGroundTruth(appConfig.EvalAnnotations)
+	if err != nil {
+		slog.Error("Erro nas anotações", logging.Err(err))
+		os.Exit(1)
+	}
+
+	objectDetector, err := NewYOLODetector(appConfig)
+	if err != nil {
+		slog.Error("Erro ao inicializar detector de objetos", logging.Err(err))
+		os.Exit(1)
+	}
+	defer objectDetector.Close()
+
+	shopliftingDetector, err := shoplifting.NewShopliftingDetector(NewYOLODetectorAdapter(objectDetector), appConfig, objectDetector.ClassNames())
+	if err != nil {
+		slog.Error("Erro ao inicializar detector de shoplifting", logging.Err(err))
+		os.Exit(1)
+	}
+	defer shopliftingDetector.Close()
+
+	frameSource, err := setupSource(appConfig)
+	if err != nil {
+		slog.Error("Erro na fonte de vídeo", logging.Err(err))
+		os.Exit(1)
+	}
+	defer frameSource.Close()
+
+	frameClock := setupClock(appConfig, frameSource, shopliftingDetector)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	evaluator := evaluation.New(groundTruth, appConfig.EvalIoUThreshold)
+
+	img := gocv.NewMat()
+	defer img.Close()
+
+	slog.Info("Avaliando contra ground-truth", logging.Icon("📏"),
+		slog.String("annotations", appConfig.EvalAnnotations),
+		slog.Int("annotated_frames", len(groundTruth.Frames)), slog.Int("incidents", len(groundTruth.Incidents)))
+
+	frameCount := 0
+	for ctx.Err() == nil {
+		if ok := frameSource.Read(&img); !ok {
+			break
+		}
+		if img.Empty() {
+			continue
+		}
+		frameCount++
+		if frameClock != nil {
+			frameClock.Tick(frameSource.Timestamp())
+		}
+
+		detections, behaviors := shopliftingDetector.DetectShoplifting(img)
+
+		evalDetections := make([]evaluation.Detection, len(detections))
+		for i, det := range detections {
+			evalDetections[i] = evaluation.Detection{ClassID: det.ClassID, Confidence: det.Confidence, Box: det.Box}
+		}
+
+		var tracks []evaluation.TrackBox
+		for _, track := range shopliftingDetector.ConfirmedTracks() {
+			tracks = append(tracks, evaluation.TrackBox{ID: track.ID, Box: track.Box})
+		}
+
+		// Apenas alertas que seriam emitidos (após throttling) contam
+		var events []evaluation.Event
+		for _, behavior := range behaviors {
+			if behavior.ShouldLog {
+				events = append(events, evaluation.Event{Type: behavior.Type, PersonID: behavior.PersonID, Frame: frameCount})
+			}
+		}
+
+		evaluator.AddFrame(frameCount, evalDetections, tracks, events)
+
+		if frameCount%100 == 0 {
+			slog.Info("Frames processados", logging.Frame(frameCount))
+		}
+	}
+
+	if ctx.Err() != nil {
+		slog.Warn("Avaliação interrompida: relatório parcial", logging.Icon("🛑"))
+	}
+
+	report := evaluator.Report(objectDetector.ClassNames())
+	report.Print(os.Stdout)
+
+	if appConfig.EvalReport != "" {
+		if err := report.Save(appConfig.EvalReport); err != nil {
+			slog.Error("Erro ao salvar relatório", logging.Err(err))
+			os.Exit(1)
+		}
+		slog.Info("Relatório salvo", logging.Icon("💾"), slog.String("path", appConfig.EvalReport))
+	}
+}
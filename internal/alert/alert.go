@@ -0,0 +1,147 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"poc-camera/config"
+)
+
+// Point representa uma coordenada em pixels no frame
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Eventos do ciclo de vida de um incidente (ver internal/incident)
+const (
+	EventOpened  = "opened"  // primeira observação do comportamento
+	EventUpdated = "updated" // nova confiança máxima
+	EventClosed  = "closed"  // comportamento deixou de ser observado
+)
+
+// Alert é o evento entregue aos sinks: um comportamento suspeito mais os
+// metadados do frame em que ocorreu (definido aqui para independência do
+// package shoplifting e do OpenCV)
+type Alert struct {
+	Type        string    `json:"type"`
+	Confidence  float32   `json:"confidence"`
+	Description string    `json:"description"`
+	Details     string    `json:"details,omitempty"`
+	PersonID    int       `json:"person_id"`
+	Location    Point     `json:"location"`
+	Timestamp   time.Time `json:"timestamp"`
+	Frame       int       `json:"frame"`
+	Source      string    `json:"source"`
+	CameraID    string    `json:"camera_id"`
+	FrameWidth  int       `json:"frame_width"`
+	FrameHeight int       `json:"frame_height"`
+
+	// Ciclo de vida do incidente (vazio em observações ainda não agregadas)
+	Event           string    `json:"event,omitempty"`
+	IncidentID      string    `json:"incident_id,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	PeakConfidence  float32   `json:"peak_confidence"`
+
+	// Evidências gravadas para o alerta (vazias se desabilitado)
+	SnapshotPath string `json:"snapshot_path,omitempty"`
+	ClipPath     string `json:"clip_path,omitempty"`
+}
+
+// Sink recebe alertas e os entrega a um destino (arquivo, webhook, MQTT...)
+type Sink interface {
+	// Send entrega o alerta; implementações assíncronas apenas enfileiram
+	Send(alert Alert) error
+	// Close finaliza o sink, entregando o que ainda estiver pendente
+	Close() error
+}
+
+// MultiSink repassa cada alerta para vários sinks
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink cria um sink que distribui alertas para todos os sinks informados
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Add inclui um sink criado fora de NewFromConfig (ex: banco de incidentes)
+func (m *MultiSink) Add(sink Sink) {
+	m.sinks = append(m.sinks, sink)
+}
+
+// Len retorna a quantidade de sinks configurados
+func (m *MultiSink) Len() int {
+	return len(m.sinks)
+}
+
+// Send implementa Sink; um sink com falha não impede a entrega aos demais
+func (m *MultiSink) Send(alert Alert) error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if err := sink.Send(alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close implementa Sink
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewFromConfig cria os sinks habilitados na configuração
+func NewFromConfig(cfg *config.Config) (*MultiSink, error) {
+	var sinks []Sink
+
+	fail := func(err error) (*MultiSink, error) {
+		NewMultiSink(sinks...).Close()
+		return nil, err
+	}
+
+	if cfg.AlertFile != "" {
+		sink, err := NewFileSink(cfg.AlertFile)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.AlertWebhookURL != "" {
+		sinks = append(sinks, NewWebhookSink(WebhookOptions{
+			URL:        cfg.AlertWebhookURL,
+			Timeout:    seconds(cfg.AlertWebhookTimeout),
+			MaxRetries: cfg.AlertWebhookRetries,
+			QueueSize:  cfg.AlertWebhookQueueSize,
+		}))
+	}
+
+	if cfg.AlertMQTTBroker != "" {
+		sink, err := NewMQTTSink(MQTTOptions{
+			Broker:   cfg.AlertMQTTBroker,
+			Topic:    cfg.AlertMQTTTopic,
+			ClientID: cfg.AlertMQTTClientID,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("erro ao conectar no broker MQTT: %v", err))
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return NewMultiSink(sinks...), nil
+}
+
+// seconds converte segundos da configuração em time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,42 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink grava cada alerta como uma linha JSON (JSON Lines)
+type FileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileSink abre (ou cria) o arquivo em modo append
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo de alertas: %v", err)
+	}
+	return &FileSink{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Send implementa Sink
+func (s *FileSink) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.encoder.Encode(alert); err != nil {
+		return fmt.Errorf("erro ao gravar alerta em %s: %v", s.file.Name(), err)
+	}
+	return nil
+}
+
+// Close implementa Sink
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,51 @@
+package alert
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSinkWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alertas.jsonl")
+	at := time.Date(2025, 1, 10, 14, 3, 22, 0, time.UTC)
+
+	// Duas aberturas: a segunda reabre o arquivo em modo append
+	for i, behavior := range []string{"PERMANENCIA_EXCESSIVA", "PROXIMIDADE_SUSPEITA"} {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Send(Alert{Type: behavior, PersonID: i + 1, Timestamp: at, Event: EventOpened, IncidentID: "cam0-1"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var alerts []Alert
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var a Alert
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			t.Fatalf("linha inválida %q: %v", scanner.Text(), err)
+		}
+		alerts = append(alerts, a)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("linhas = %d, esperado 2", len(alerts))
+	}
+	if a := alerts[1]; a.Type != "PROXIMIDADE_SUSPEITA" || a.PersonID != 2 || !a.Timestamp.Equal(at) || a.Event != EventOpened {
+		t.Errorf("alerta lido = %+v", a)
+	}
+}
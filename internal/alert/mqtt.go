@@ -0,0 +1,162 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"poc-camera/internal/logging"
+)
+
+// Valores padrão do MQTT
+const (
+	defaultMQTTTopic          = "poc-camera/alertas"
+	defaultMQTTClientID       = "poc-camera"
+	defaultMQTTQueueSize      = 100
+	defaultMQTTPublishTimeout = 5 * time.Second
+	mqttConnectTimeout        = 10 * time.Second
+	mqttDrainTimeout          = 10 * time.Second
+	mqttQoS                   = 1 // pelo menos uma entrega
+)
+
+// MQTTOptions configura o MQTTSink
+type MQTTOptions struct {
+	Broker         string // ex: tcp://localhost:1883
+	Topic          string
+	ClientID       string
+	QueueSize      int           // alertas aguardando publicação
+	PublishTimeout time.Duration // espera pela confirmação (PUBACK) de cada alerta
+}
+
+// MQTTSink publica cada alerta como JSON em um tópico MQTT em segundo plano.
+// Send apenas enfileira (um broker lento não trava a análise); um worker
+// publica em ordem. O cliente reconecta automaticamente e mantém as
+// publicações pendentes enquanto o broker estiver fora.
+type MQTTSink struct {
+	client mqtt.Client
+	opts   MQTTOptions
+	queue  chan Alert
+	done   chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	failed int // alertas cuja publicação falhou ou não foi confirmada
+}
+
+// NewMQTTSink conecta no broker e inicia o worker de publicação
+func NewMQTTSink(opts MQTTOptions) (*MQTTSink, error) {
+	if opts.Topic == "" {
+		opts.Topic = defaultMQTTTopic
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = defaultMQTTClientID
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultMQTTQueueSize
+	}
+	if opts.PublishTimeout <= 0 {
+		opts.PublishTimeout = defaultMQTTPublishTimeout
+	}
+
+	clientOpts := mqtt.NewClientOptions().
+		AddBroker(opts.Broker).
+		SetClientID(opts.ClientID).
+		SetAutoReconnect(true).
+		SetConnectTimeout(mqttConnectTimeout)
+
+	client := mqtt.NewClient(clientOpts)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttConnectTimeout) {
+		return nil, fmt.Errorf("timeout ao conectar em %s", opts.Broker)
+	}
+	if err := token.Error(); err != nil {
+		return nil, err
+	}
+
+	s := &MQTTSink{
+		client: client,
+		opts:   opts,
+		queue:  make(chan Alert, opts.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Send implementa Sink; não bloqueia e retorna ErrQueueFull se a fila estiver cheia
+func (s *MQTTSink) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("MQTT encerrado")
+	}
+
+	select {
+	case s.queue <- alert:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Failed retorna quantos alertas não tiveram a publicação confirmada
+func (s *MQTTSink) Failed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed
+}
+
+// Close implementa Sink; aguarda a publicação dos alertas pendentes por até
+// 10 segundos e desconecta
+func (s *MQTTSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	var err error
+	select {
+	case <-s.done:
+	case <-time.After(mqttDrainTimeout):
+		err = fmt.Errorf("MQTT encerrado com %d alertas não publicados", len(s.queue))
+	}
+	s.client.Disconnect(250)
+	return err
+}
+
+// run publica os alertas da fila em ordem
+func (s *MQTTSink) run() {
+	defer close(s.done)
+
+	for alert := range s.queue {
+		if err := s.publish(alert); err != nil {
+			s.mu.Lock()
+			s.failed++
+			s.mu.Unlock()
+			slog.Warn("MQTT: alerta não publicado", logging.Camera(alert.CameraID), logging.TrackID(alert.PersonID), logging.Behavior(alert.Type), logging.Err(err))
+		}
+	}
+}
+
+// publish publica um alerta e aguarda a confirmação do broker
+func (s *MQTTSink) publish(alert Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	token := s.client.Publish(s.opts.Topic, mqttQoS, false, payload)
+	if !token.WaitTimeout(s.opts.PublishTimeout) {
+		return fmt.Errorf("timeout ao publicar alerta em %s", s.opts.Topic)
+	}
+	return token.Error()
+}
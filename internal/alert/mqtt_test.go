@@ -0,0 +1,143 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// fakeBroker é um broker MQTT mínimo em processo: aceita conexões, guarda
+// as publicações e confirma as de QoS 1 (a menos que stall esteja ativo,
+// simulando um broker lento)
+type fakeBroker struct {
+	listener net.Listener
+	stall    bool
+
+	mu        sync.Mutex
+	published []*packets.PublishPacket
+}
+
+func newFakeBroker(t *testing.T, stall bool) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{listener: listener, stall: stall}
+	go b.serve()
+	t.Cleanup(func() { listener.Close() })
+	return b
+}
+
+func (b *fakeBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			packets.NewControlPacket(packets.Connack).Write(conn)
+		case *packets.PublishPacket:
+			b.mu.Lock()
+			b.published = append(b.published, p)
+			b.mu.Unlock()
+			if p.Qos == 1 && !b.stall {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				ack.Write(conn)
+			}
+		case *packets.PingreqPacket:
+			packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) messages() []*packets.PublishPacket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*packets.PublishPacket(nil), b.published...)
+}
+
+func TestMQTTPublish(t *testing.T) {
+	broker := newFakeBroker(t, false)
+	sink, err := NewMQTTSink(MQTTOptions{Broker: broker.url(), Topic: "loja/alertas", ClientID: "teste"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := sink.Send(Alert{Type: "PROXIMIDADE_SUSPEITA", PersonID: i, Event: EventOpened}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	messages := broker.messages()
+	if len(messages) != 3 {
+		t.Fatalf("publicações = %d, esperado 3", len(messages))
+	}
+	for i, msg := range messages {
+		var a Alert
+		if err := json.Unmarshal(msg.Payload, &a); err != nil {
+			t.Fatalf("payload inválido: %v", err)
+		}
+		if msg.TopicName != "loja/alertas" || msg.Qos != mqttQoS || a.PersonID != i+1 {
+			t.Errorf("publicação %d: tópico %q, QoS %d, alerta %+v", i, msg.TopicName, msg.Qos, a)
+		}
+	}
+	if sink.Failed() != 0 {
+		t.Errorf("falhas = %d, esperado 0", sink.Failed())
+	}
+}
+
+func TestMQTTSlowBrokerDoesNotBlockSend(t *testing.T) {
+	broker := newFakeBroker(t, true)
+	sink, err := NewMQTTSink(MQTTOptions{Broker: broker.url(), QueueSize: 2, PublishTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem PUBACK, cada publicação espera o timeout; Send só enfileira
+	start := time.Now()
+	var full int
+	for i := 0; i < 5; i++ {
+		if err := sink.Send(Alert{PersonID: i}); errors.Is(err, ErrQueueFull) {
+			full++
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Send bloqueou por %v com broker lento", elapsed)
+	}
+	if full == 0 {
+		t.Error("fila de 2 alertas deveria descartar parte dos 5 envios")
+	}
+
+	sink.Close()
+	if got := sink.Failed(); got != 5-full {
+		t.Errorf("publicações sem confirmação = %d, esperado %d", got, 5-full)
+	}
+}
@@ -0,0 +1,200 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"poc-camera/internal/logging"
+)
+
+// Valores padrão do webhook
+const (
+	defaultWebhookTimeout   = 5 * time.Second
+	defaultWebhookQueueSize = 100
+	defaultInitialBackoff   = 500 * time.Millisecond
+	defaultMaxBackoff       = 30 * time.Second
+	webhookDrainTimeout     = 10 * time.Second
+)
+
+// ErrQueueFull indica que o alerta foi descartado porque a fila do sink
+// assíncrono (webhook ou MQTT) está cheia
+var ErrQueueFull = errors.New("fila de alertas cheia")
+
+// WebhookOptions configura o WebhookSink
+type WebhookOptions struct {
+	URL            string
+	Timeout        time.Duration // timeout de cada requisição
+	MaxRetries     int           // novas tentativas após a primeira falha
+	QueueSize      int           // alertas aguardando envio
+	InitialBackoff time.Duration // espera antes da primeira nova tentativa (dobra a cada falha)
+	MaxBackoff     time.Duration
+	Client         *http.Client // opcional
+}
+
+// WebhookSink envia alertas via HTTP POST (JSON) em segundo plano.
+// Send apenas enfileira; um worker entrega em ordem, repetindo com backoff
+// exponencial quando o servidor falha (erro de rede ou status 5xx/429).
+type WebhookSink struct {
+	opts   WebhookOptions
+	queue  chan Alert
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	failed int // alertas descartados após esgotar as tentativas
+}
+
+// NewWebhookSink cria o sink e inicia o worker de envio
+func NewWebhookSink(opts WebhookOptions) *WebhookSink {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultWebhookTimeout
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultWebhookQueueSize
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: opts.Timeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &WebhookSink{
+		opts:   opts,
+		queue:  make(chan Alert, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Send implementa Sink; não bloqueia e retorna ErrQueueFull se a fila estiver cheia
+func (s *WebhookSink) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("webhook encerrado")
+	}
+
+	select {
+	case s.queue <- alert:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Failed retorna quantos alertas foram descartados após esgotar as tentativas
+func (s *WebhookSink) Failed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed
+}
+
+// Close implementa Sink; aguarda a entrega dos alertas pendentes por até 10 segundos
+func (s *WebhookSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(webhookDrainTimeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+
+	if pending := len(s.queue); pending > 0 {
+		return fmt.Errorf("webhook encerrado com %d alertas não entregues", pending)
+	}
+	return nil
+}
+
+// run entrega os alertas da fila em ordem
+func (s *WebhookSink) run() {
+	defer close(s.done)
+
+	for alert := range s.queue {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err := s.deliver(alert); err != nil {
+			s.mu.Lock()
+			s.failed++
+			s.mu.Unlock()
+			slog.Warn("Webhook: alerta descartado", logging.Camera(alert.CameraID), logging.TrackID(alert.PersonID), logging.Behavior(alert.Type), logging.Err(err))
+		}
+	}
+}
+
+// deliver envia um alerta, repetindo com backoff exponencial
+func (s *WebhookSink) deliver(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.opts.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.opts.MaxRetries {
+			return fmt.Errorf("%v (tentativas: %d)", err, attempt+1)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+	}
+}
+
+// post faz uma requisição e indica se a falha é temporária (vale repetir)
+func (s *WebhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("webhook respondeu %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook respondeu %s", resp.Status)
+	}
+}
@@ -0,0 +1,134 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer responde com os status informados em sequência (o último
+// se repete) e guarda os alertas recebidos
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	received []Alert
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	ws := &webhookServer{statuses: statuses}
+	ws.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a Alert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("corpo inválido: %v", err)
+		}
+
+		ws.mu.Lock()
+		status := ws.statuses[min(len(ws.received), len(ws.statuses)-1)]
+		ws.received = append(ws.received, a)
+		ws.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ws.Close)
+	return ws
+}
+
+func (ws *webhookServer) requests() int {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return len(ws.received)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		retries    int
+		wantReqs   int
+		wantFailed int
+	}{
+		{name: "5xx e depois sucesso", statuses: []int{503, 500, 200}, retries: 3, wantReqs: 3, wantFailed: 0},
+		{name: "429 é repetido", statuses: []int{429, 204}, retries: 3, wantReqs: 2, wantFailed: 0},
+		{name: "tentativas esgotadas", statuses: []int{500}, retries: 2, wantReqs: 3, wantFailed: 1},
+		{name: "4xx não é repetido", statuses: []int{400}, retries: 3, wantReqs: 1, wantFailed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebhookServer(t, tt.statuses...)
+			sink := NewWebhookSink(WebhookOptions{
+				URL:            server.URL,
+				MaxRetries:     tt.retries,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     4 * time.Millisecond,
+			})
+
+			if err := sink.Send(Alert{Type: "PROXIMIDADE_SUSPEITA", PersonID: 7}); err != nil {
+				t.Fatal(err)
+			}
+			sink.Close()
+
+			if got := server.requests(); got != tt.wantReqs {
+				t.Errorf("requisições = %d, esperado %d", got, tt.wantReqs)
+			}
+			if got := sink.Failed(); got != tt.wantFailed {
+				t.Errorf("descartados = %d, esperado %d", got, tt.wantFailed)
+			}
+			if server.received[0].PersonID != 7 {
+				t.Errorf("alerta recebido = %+v", server.received[0])
+			}
+		})
+	}
+}
+
+func TestWebhookQueueFullAndDrain(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var received []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a Alert
+		json.NewDecoder(r.Body).Decode(&a)
+		mu.Lock()
+		received = append(received, a.PersonID)
+		first := len(received) == 1
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(WebhookOptions{URL: server.URL, QueueSize: 1})
+
+	// O worker fica preso no primeiro alerta; o segundo ocupa a fila
+	if err := sink.Send(Alert{PersonID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := sink.Send(Alert{PersonID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(Alert{PersonID: 3}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Send com fila cheia = %v, esperado ErrQueueFull", err)
+	}
+
+	// Close entrega o que estava na fila antes de retornar
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[1] != 2 {
+		t.Errorf("alertas entregues = %v, esperado [1 2]", received)
+	}
+	if err := sink.Send(Alert{PersonID: 4}); err == nil {
+		t.Error("Send após Close deveria falhar")
+	}
+}
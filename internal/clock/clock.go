@@ -0,0 +1,91 @@
+// Package clock abstrai a origem do tempo usada pela análise comportamental,
+// permitindo reproduzir vídeos em qualquer velocidade com resultados
+// idênticos e avançar o tempo de forma determinística em testes.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock fornece o instante atual
+type Clock interface {
+	Now() time.Time
+}
+
+// System é o relógio de parede
+type System struct{}
+
+// Now implementa Clock
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Manual é um relógio controlado explicitamente (testes e replays)
+type Manual struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManual cria um relógio manual parado em start
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now implementa Clock
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set define o instante atual
+func (m *Manual) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = t
+}
+
+// Advance avança o relógio em d
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+// Frame é um relógio guiado pelos timestamps dos frames: o instante atual é
+// o início do replay mais a posição do frame na mídia. Fontes sem timestamp
+// usam o índice do frame dividido pela taxa de quadros.
+type Frame struct {
+	*Manual
+	start    time.Time
+	interval time.Duration
+	last     time.Duration
+	frames   int
+}
+
+// NewFrame cria um relógio de frames. fps é usado quando o frame não tem timestamp.
+func NewFrame(start time.Time, fps float64) *Frame {
+	return &Frame{
+		Manual:   NewManual(start),
+		start:    start,
+		interval: time.Duration(float64(time.Second) / fps),
+	}
+}
+
+// Tick posiciona o relógio no frame recém-lido. pts é a posição do frame na
+// mídia; ok indica se a fonte a informou. O tempo nunca anda para trás:
+// timestamps ausentes ou fora de ordem avançam um intervalo de frame.
+func (f *Frame) Tick(pts time.Duration, ok bool) {
+	next := f.last + f.interval
+	if f.frames == 0 {
+		next = 0
+	}
+	if ok && (f.frames == 0 || pts > f.last) {
+		next = pts
+	}
+
+	f.frames++
+	f.last = next
+	f.Set(f.start.Add(next))
+}
@@ -0,0 +1,115 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+)
+
+// PersonClassID é a classe das pessoas, as únicas avaliadas no tracking
+const PersonClassID = 0
+
+// GroundTruth contém as anotações de referência de um vídeo.
+//
+// Formato JSON:
+//
+//	{
+//	  "frames": [
+//	    {"frame": 1, "objects": [{"id": 1, "class_id": 0, "box": [100, 80, 180, 300]}]}
+//	  ],
+//	  "incidents": [
+//	    {"type": "PROXIMIDADE_SUSPEITA", "start_frame": 120, "end_frame": 260}
+//	  ]
+//	}
+//
+// Frames são numerados a partir de 1, na ordem de leitura da fonte. Apenas
+// frames presentes em "frames" entram nas métricas de detecção e tracking;
+// objetos da classe pessoa precisam de "id" para o tracking.
+type GroundTruth struct {
+	Frames    []FrameAnnotation `json:"frames"`
+	Incidents []Incident        `json:"incidents"`
+}
+
+// FrameAnnotation lista os objetos anotados em um frame
+type FrameAnnotation struct {
+	Frame   int      `json:"frame"`
+	Objects []Object `json:"objects"`
+}
+
+// Object é um objeto anotado
+type Object struct {
+	ID      int    `json:"id"`
+	ClassID int    `json:"class_id"`
+	Box     [4]int `json:"box"` // x1, y1, x2, y2
+}
+
+// Rect retorna a caixa do objeto
+func (o Object) Rect() image.Rectangle {
+	return image.Rect(o.Box[0], o.Box[1], o.Box[2], o.Box[3])
+}
+
+// Incident é um intervalo de frames (inclusivo) com um comportamento suspeito real
+type Incident struct {
+	Type       string `json:"type"`
+	StartFrame int    `json:"start_frame"`
+	EndFrame   int    `json:"end_frame"`
+}
+
+// LoadGroundTruth lê e valida um arquivo de anotações
+func LoadGroundTruth(path string) (*GroundTruth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler anotações: %v", err)
+	}
+
+	var gt GroundTruth
+	if err := json.Unmarshal(data, &gt); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar anotações %s: %v", path, err)
+	}
+	if err := gt.validate(); err != nil {
+		return nil, fmt.Errorf("anotações inválidas em %s: %v", path, err)
+	}
+	return &gt, nil
+}
+
+// validate verifica frames repetidos, caixas e intervalos
+func (gt *GroundTruth) validate() error {
+	seen := make(map[int]bool, len(gt.Frames))
+	for _, f := range gt.Frames {
+		if f.Frame < 1 {
+			return fmt.Errorf("frame %d: numeração começa em 1", f.Frame)
+		}
+		if seen[f.Frame] {
+			return fmt.Errorf("frame %d anotado mais de uma vez", f.Frame)
+		}
+		seen[f.Frame] = true
+
+		ids := make(map[int]bool)
+		for _, obj := range f.Objects {
+			if obj.Rect().Empty() {
+				return fmt.Errorf("frame %d: caixa vazia %v", f.Frame, obj.Box)
+			}
+			if obj.ClassID != PersonClassID {
+				continue
+			}
+			if obj.ID <= 0 {
+				return fmt.Errorf("frame %d: pessoa sem id", f.Frame)
+			}
+			if ids[obj.ID] {
+				return fmt.Errorf("frame %d: id %d repetido", f.Frame, obj.ID)
+			}
+			ids[obj.ID] = true
+		}
+	}
+
+	for i, inc := range gt.Incidents {
+		if inc.Type == "" {
+			return fmt.Errorf("incidente %d sem tipo", i)
+		}
+		if inc.StartFrame < 1 || inc.EndFrame < inc.StartFrame {
+			return fmt.Errorf("incidente %d: intervalo inválido %d-%d", i, inc.StartFrame, inc.EndFrame)
+		}
+	}
+	return nil
+}
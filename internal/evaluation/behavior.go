@@ -0,0 +1,77 @@
+package evaluation
+
+import "sort"
+
+// Event é um alerta de comportamento emitido em um frame
+type Event struct {
+	Type     string
+	PersonID int
+	Frame    int
+}
+
+// BehaviorMetrics resume a qualidade dos alertas de um tipo de comportamento.
+// A precisão é medida sobre os alertas emitidos (alerta dentro de um incidente
+// do mesmo tipo é verdadeiro positivo); o recall, sobre os incidentes anotados
+// (incidente com ao menos um alerta é detectado).
+type BehaviorMetrics struct {
+	Type              string  `json:"type"`
+	Alerts            int     `json:"alerts"`
+	TruePositives     int     `json:"true_positives"`
+	Incidents         int     `json:"incidents"`
+	DetectedIncidents int     `json:"detected_incidents"`
+	Precision         float64 `json:"precision"`
+	Recall            float64 `json:"recall"`
+}
+
+// evaluateBehaviors compara os alertas com os intervalos anotados, por tipo
+func evaluateBehaviors(incidents []Incident, events []Event) []BehaviorMetrics {
+	byType := make(map[string]*BehaviorMetrics)
+	metricsFor := func(behaviorType string) *BehaviorMetrics {
+		m, ok := byType[behaviorType]
+		if !ok {
+			m = &BehaviorMetrics{Type: behaviorType}
+			byType[behaviorType] = m
+		}
+		return m
+	}
+
+	detected := make([]bool, len(incidents))
+	for _, inc := range incidents {
+		metricsFor(inc.Type).Incidents++
+	}
+
+	for _, ev := range events {
+		m := metricsFor(ev.Type)
+		m.Alerts++
+
+		hit := false
+		for i, inc := range incidents {
+			if inc.Type == ev.Type && ev.Frame >= inc.StartFrame && ev.Frame <= inc.EndFrame {
+				detected[i] = true
+				hit = true
+			}
+		}
+		if hit {
+			m.TruePositives++
+		}
+	}
+
+	for i, inc := range incidents {
+		if detected[i] {
+			metricsFor(inc.Type).DetectedIncidents++
+		}
+	}
+
+	result := make([]BehaviorMetrics, 0, len(byType))
+	for _, m := range byType {
+		if m.Alerts > 0 {
+			m.Precision = float64(m.TruePositives) / float64(m.Alerts)
+		}
+		if m.Incidents > 0 {
+			m.Recall = float64(m.DetectedIncidents) / float64(m.Incidents)
+		}
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
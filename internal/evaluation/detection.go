@@ -0,0 +1,117 @@
+package evaluation
+
+import (
+	"image"
+	"sort"
+
+	"poc-camera/internal/tracking"
+)
+
+// Detection é uma detecção produzida pelo modelo em um frame
+type Detection struct {
+	ClassID    int
+	Confidence float32
+	Box        image.Rectangle
+}
+
+// scoredMatch registra se uma predição casou com algum ground-truth
+type scoredMatch struct {
+	confidence float32
+	truePos    bool
+}
+
+// detectionAccumulator acumula casamentos por classe para calcular o AP
+type detectionAccumulator struct {
+	iouThreshold float64
+	predictions  map[int][]scoredMatch
+	groundTruth  map[int]int
+}
+
+func newDetectionAccumulator(iouThreshold float64) *detectionAccumulator {
+	return &detectionAccumulator{
+		iouThreshold: iouThreshold,
+		predictions:  make(map[int][]scoredMatch),
+		groundTruth:  make(map[int]int),
+	}
+}
+
+// add casa as detecções de um frame com os objetos anotados. Cada classe é
+// casada de forma gulosa por confiança decrescente, como no PASCAL VOC.
+func (da *detectionAccumulator) add(objects []Object, detections []Detection) {
+	for _, obj := range objects {
+		da.groundTruth[obj.ClassID]++
+	}
+
+	sorted := make([]Detection, len(detections))
+	copy(sorted, detections)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Confidence > sorted[j].Confidence
+	})
+
+	used := make([]bool, len(objects))
+	for _, det := range sorted {
+		best, bestIoU := -1, da.iouThreshold
+		for i, obj := range objects {
+			if used[i] || obj.ClassID != det.ClassID {
+				continue
+			}
+			if iou := tracking.IoU(det.Box, obj.Rect()); iou >= bestIoU {
+				best, bestIoU = i, iou
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+		}
+		da.predictions[det.ClassID] = append(da.predictions[det.ClassID], scoredMatch{
+			confidence: det.Confidence,
+			truePos:    best >= 0,
+		})
+	}
+}
+
+// averagePrecision calcula o AP por classe (interpolação em todos os pontos).
+// Classes sem ground-truth não entram no resultado.
+func (da *detectionAccumulator) averagePrecision() map[int]float64 {
+	ap := make(map[int]float64, len(da.groundTruth))
+	for classID, total := range da.groundTruth {
+		if total == 0 {
+			continue
+		}
+		ap[classID] = computeAP(da.predictions[classID], total)
+	}
+	return ap
+}
+
+// computeAP calcula a área sob a curva precisão × recall
+func computeAP(matches []scoredMatch, totalGT int) float64 {
+	sorted := make([]scoredMatch, len(matches))
+	copy(sorted, matches)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].confidence > sorted[j].confidence
+	})
+
+	recall := make([]float64, len(sorted))
+	precision := make([]float64, len(sorted))
+	tp, fp := 0, 0
+	for i, m := range sorted {
+		if m.truePos {
+			tp++
+		} else {
+			fp++
+		}
+		recall[i] = float64(tp) / float64(totalGT)
+		precision[i] = float64(tp) / float64(tp+fp)
+	}
+
+	// Envelope monotônico da precisão, de trás para frente
+	for i := len(precision) - 2; i >= 0; i-- {
+		precision[i] = max(precision[i], precision[i+1])
+	}
+
+	ap, prevRecall := 0.0, 0.0
+	for i := range sorted {
+		ap += (recall[i] - prevRecall) * precision[i]
+		prevRecall = recall[i]
+	}
+	return ap
+}
@@ -0,0 +1,123 @@
+package evaluation
+
+import (
+	"image"
+	"testing"
+)
+
+func TestComputeAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []scoredMatch
+		totalGT int
+		want    float64
+	}{
+		{
+			name:    "todas corretas",
+			matches: []scoredMatch{{0.9, true}, {0.8, true}},
+			totalGT: 2,
+			want:    1,
+		},
+		{
+			// precisão 1, 1/2, 2/3 nos recalls 1/2, 1/2, 1; o envelope eleva o
+			// ponto do meio a 2/3: AP = 1/2·1 + 1/2·2/3
+			name:    "falso positivo entre acertos",
+			matches: []scoredMatch{{0.7, true}, {0.9, true}, {0.8, false}},
+			totalGT: 2,
+			want:    0.5 + 0.5*2.0/3,
+		},
+		{
+			name:    "objeto nunca detectado limita o recall",
+			matches: []scoredMatch{{0.9, true}},
+			totalGT: 2,
+			want:    0.5,
+		},
+		{
+			name:    "sem predições",
+			totalGT: 3,
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeAP(tt.matches, tt.totalGT); !approxEqual(got, tt.want) {
+				t.Errorf("AP = %.4f, esperado %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectionAveragePrecision(t *testing.T) {
+	person := func(x int) Object { return Object{ID: x, ClassID: 0, Box: [4]int{x, 0, x + 50, 100}} }
+	bag := func(x int) Object { return Object{ClassID: 1, Box: [4]int{x, 200, x + 40, 240}} }
+	detect := func(obj Object, confidence float32) Detection {
+		return Detection{ClassID: obj.ClassID, Confidence: confidence, Box: obj.Rect()}
+	}
+
+	da := newDetectionAccumulator(0.5)
+
+	// Frame 1: duas pessoas e uma bolsa. A segunda detecção da pessoa 1 é
+	// duplicada (FP) e a bolsa é detectada como pessoa (FP da classe 0).
+	p1, p2, b1 := person(1), person(200), bag(400)
+	da.add([]Object{p1, p2, b1}, []Detection{
+		detect(p1, 0.9),
+		detect(p1, 0.8),
+		{ClassID: 0, Confidence: 0.3, Box: b1.Rect()},
+	})
+
+	// Frame 2: uma pessoa detectada com confiança baixa e uma bolsa detectada
+	// fora do lugar (FP) antes da correta
+	p3, b2 := person(600), bag(100)
+	da.add([]Object{p3, b2}, []Detection{
+		detect(p3, 0.7),
+		{ClassID: 1, Confidence: 0.95, Box: image.Rect(700, 700, 740, 740)},
+		detect(b2, 0.6),
+	})
+
+	// Frame 3: classe sem ground-truth não entra no resultado
+	da.add(nil, []Detection{{ClassID: 2, Confidence: 0.9, Box: image.Rect(0, 0, 10, 10)}})
+
+	// Pessoas (3 objetos): 0.9 VP, 0.8 FP, 0.7 VP, 0.3 FP
+	//   precisão 1, 1/2, 2/3, 1/2; recall 1/3, 1/3, 2/3, 2/3
+	//   AP = 1/3·1 + 1/3·2/3 = 5/9
+	// Bolsas (2 objetos): 0.95 FP, 0.6 VP
+	//   precisão 0, 1/2; recall 0, 1/2 → AP = 1/2·1/2 = 1/4
+	want := map[int]float64{0: 5.0 / 9, 1: 0.25}
+
+	got := da.averagePrecision()
+	if len(got) != len(want) {
+		t.Fatalf("classes = %v, esperado %v", got, want)
+	}
+	for classID, ap := range want {
+		if !approxEqual(got[classID], ap) {
+			t.Errorf("AP da classe %d = %.4f, esperado %.4f", classID, got[classID], ap)
+		}
+	}
+}
+
+func TestReportMeanAveragePrecision(t *testing.T) {
+	gt := &GroundTruth{Frames: []FrameAnnotation{{
+		Frame: 1,
+		Objects: []Object{
+			{ID: 1, ClassID: 0, Box: [4]int{0, 0, 50, 100}},
+			{ClassID: 1, Box: [4]int{100, 100, 140, 140}},
+		},
+	}}}
+
+	e := New(gt, 0.5)
+	e.AddFrame(1, []Detection{{ClassID: 0, Confidence: 0.9, Box: image.Rect(0, 0, 50, 100)}}, nil, nil)
+	e.AddFrame(2, []Detection{{ClassID: 1, Confidence: 0.9, Box: image.Rect(100, 100, 140, 140)}}, nil, nil)
+
+	report := e.Report([]string{"pessoa", "bolsa"})
+	if report.Frames != 2 || report.AnnotatedFrames != 1 {
+		t.Errorf("frames = %d/%d, esperado 2/1", report.Frames, report.AnnotatedFrames)
+	}
+	// A bolsa só foi detectada em um frame não anotado: AP 0, mAP = (1 + 0)/2
+	if !approxEqual(report.Detection.MAP, 0.5) {
+		t.Errorf("mAP = %.4f, esperado 0.5", report.Detection.MAP)
+	}
+	if len(report.Detection.Classes) != 2 || report.Detection.Classes[1].Label != "bolsa" {
+		t.Errorf("classes = %+v, esperado pessoa e bolsa", report.Detection.Classes)
+	}
+}
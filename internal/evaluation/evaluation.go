@@ -0,0 +1,152 @@
+// Package evaluation mede a qualidade do pipeline contra anotações
+// ground-truth: precisão/recall por comportamento, MOTA/IDF1 do tracking e
+// mAP da detecção.
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Evaluator acumula os resultados frame a frame
+type Evaluator struct {
+	groundTruth *GroundTruth
+	annotated   map[int][]Object
+	detection   *detectionAccumulator
+	mot         *motAccumulator
+	events      []Event
+	frames      int
+}
+
+// New cria um avaliador. iouThreshold é o IoU mínimo para casar uma
+// predição (detecção ou trilha) com um objeto anotado.
+func New(gt *GroundTruth, iouThreshold float64) *Evaluator {
+	annotated := make(map[int][]Object, len(gt.Frames))
+	for _, f := range gt.Frames {
+		annotated[f.Frame] = f.Objects
+	}
+
+	return &Evaluator{
+		groundTruth: gt,
+		annotated:   annotated,
+		detection:   newDetectionAccumulator(iouThreshold),
+		mot:         newMOTAccumulator(iouThreshold),
+	}
+}
+
+// AddFrame registra as predições de um frame (numerado a partir de 1).
+// Detecções e trilhas só são avaliadas em frames anotados; alertas, em todos.
+func (e *Evaluator) AddFrame(frame int, detections []Detection, tracks []TrackBox, events []Event) {
+	e.frames = max(e.frames, frame)
+	e.events = append(e.events, events...)
+
+	objects, ok := e.annotated[frame]
+	if !ok {
+		return
+	}
+	e.detection.add(objects, detections)
+
+	var people []Object
+	for _, obj := range objects {
+		if obj.ClassID == PersonClassID {
+			people = append(people, obj)
+		}
+	}
+	e.mot.add(people, tracks)
+}
+
+// ClassAP é o AP de uma classe
+type ClassAP struct {
+	ClassID int     `json:"class_id"`
+	Label   string  `json:"label"`
+	AP      float64 `json:"ap"`
+}
+
+// DetectionMetrics resume a qualidade da detecção
+type DetectionMetrics struct {
+	MAP     float64   `json:"map"`
+	Classes []ClassAP `json:"classes"`
+}
+
+// Report é o resultado da avaliação
+type Report struct {
+	Frames          int               `json:"frames"`
+	AnnotatedFrames int               `json:"annotated_frames"`
+	IoUThreshold    float64           `json:"iou_threshold"`
+	Behaviors       []BehaviorMetrics `json:"behaviors"`
+	Tracking        TrackingMetrics   `json:"tracking"`
+	Detection       DetectionMetrics  `json:"detection"`
+}
+
+// Report calcula as métricas finais. classNames nomeia as classes no relatório.
+func (e *Evaluator) Report(classNames []string) Report {
+	report := Report{
+		Frames:          e.frames,
+		AnnotatedFrames: len(e.annotated),
+		IoUThreshold:    e.detection.iouThreshold,
+		Behaviors:       evaluateBehaviors(e.groundTruth.Incidents, e.events),
+		Tracking:        e.mot.metrics(),
+	}
+
+	for classID, ap := range e.detection.averagePrecision() {
+		label := fmt.Sprintf("classe %d", classID)
+		if classID >= 0 && classID < len(classNames) {
+			label = classNames[classID]
+		}
+		report.Detection.Classes = append(report.Detection.Classes, ClassAP{ClassID: classID, Label: label, AP: ap})
+		report.Detection.MAP += ap
+	}
+	if n := len(report.Detection.Classes); n > 0 {
+		report.Detection.MAP /= float64(n)
+	}
+	sort.Slice(report.Detection.Classes, func(i, j int) bool {
+		return report.Detection.Classes[i].ClassID < report.Detection.Classes[j].ClassID
+	})
+
+	return report
+}
+
+// Print escreve o relatório em formato legível
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(w, "📏 RELATÓRIO DE AVALIAÇÃO")
+	fmt.Fprintf(w, "   • Frames: %d (%d anotados) | IoU mínimo: %.2f\n", r.Frames, r.AnnotatedFrames, r.IoUThreshold)
+
+	fmt.Fprintln(w, "🚨 Comportamentos:")
+	if len(r.Behaviors) == 0 {
+		fmt.Fprintln(w, "   • Nenhum alerta nem incidente anotado")
+	}
+	for _, b := range r.Behaviors {
+		fmt.Fprintf(w, "   • %s: precisão %.1f%% (%d/%d alertas) | recall %.1f%% (%d/%d incidentes)\n",
+			b.Type, b.Precision*100, b.TruePositives, b.Alerts, b.Recall*100, b.DetectedIncidents, b.Incidents)
+	}
+
+	t := r.Tracking
+	fmt.Fprintln(w, "👣 Tracking:")
+	fmt.Fprintf(w, "   • MOTA: %.1f%% | MOTP: %.3f | IDF1: %.1f%% (IDP %.1f%%, IDR %.1f%%)\n",
+		t.MOTA*100, t.MOTP, t.IDF1*100, t.IDPrecision*100, t.IDRecall*100)
+	fmt.Fprintf(w, "   • Ground-truth: %d | Perdidos: %d | Falsos positivos: %d | Trocas de ID: %d\n",
+		t.GroundTruth, t.Misses, t.FalsePositives, t.IDSwitches)
+
+	fmt.Fprintln(w, "🎯 Detecção:")
+	fmt.Fprintf(w, "   • mAP@%.2f: %.1f%%\n", r.IoUThreshold, r.Detection.MAP*100)
+	for _, c := range r.Detection.Classes {
+		fmt.Fprintf(w, "     - %s: AP %.1f%%\n", c.Label, c.AP*100)
+	}
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+// Save grava o relatório em JSON
+func (r Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar relatório: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("erro ao salvar relatório: %v", err)
+	}
+	return nil
+}
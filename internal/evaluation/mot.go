@@ -0,0 +1,212 @@
+package evaluation
+
+import (
+	"image"
+	"math"
+
+	"poc-camera/internal/tracking"
+)
+
+// TrackBox é a caixa de uma trilha confirmada em um frame
+type TrackBox struct {
+	ID  int
+	Box image.Rectangle
+}
+
+// idPair identifica um par (ground-truth, trilha) para o IDF1
+type idPair struct {
+	gt, pred int
+}
+
+// motAccumulator acumula as métricas CLEAR MOT e de identidade
+type motAccumulator struct {
+	iouThreshold float64
+
+	// CLEAR MOT
+	groundTruth  int
+	misses       int
+	falsePos     int
+	idSwitches   int
+	matches      int
+	iouSum       float64
+	lastMatch    map[int]int // ground-truth → última trilha casada
+	currentMatch map[int]int // casamentos do frame anterior
+
+	// Identidades (IDF1)
+	gtFrames       map[int]int // frames em que cada id de ground-truth aparece
+	predFrames     map[int]int // frames em que cada trilha aparece
+	pairOverlaps   map[idPair]int
+	predictedBoxes int
+}
+
+func newMOTAccumulator(iouThreshold float64) *motAccumulator {
+	return &motAccumulator{
+		iouThreshold: iouThreshold,
+		lastMatch:    make(map[int]int),
+		currentMatch: make(map[int]int),
+		gtFrames:     make(map[int]int),
+		predFrames:   make(map[int]int),
+		pairOverlaps: make(map[idPair]int),
+	}
+}
+
+// add processa um frame. Os casamentos do frame anterior que continuam
+// válidos são mantidos; os demais são resolvidos pelo algoritmo húngaro
+// sobre 1 - IoU.
+func (ma *motAccumulator) add(people []Object, tracks []TrackBox) {
+	ma.groundTruth += len(people)
+	ma.predictedBoxes += len(tracks)
+	for _, p := range people {
+		ma.gtFrames[p.ID]++
+	}
+	for _, t := range tracks {
+		ma.predFrames[t.ID]++
+	}
+
+	iou := make([][]float64, len(people))
+	for i, p := range people {
+		iou[i] = make([]float64, len(tracks))
+		for j, t := range tracks {
+			iou[i][j] = tracking.IoU(p.Rect(), t.Box)
+			if iou[i][j] >= ma.iouThreshold {
+				ma.pairOverlaps[idPair{p.ID, t.ID}]++
+			}
+		}
+	}
+
+	matched := make(map[int]int, len(people)) // índice gt → índice trilha
+	usedTracks := make(map[int]bool, len(tracks))
+
+	// 1. Mantém casamentos do frame anterior ainda válidos
+	for i, p := range people {
+		prevID, ok := ma.currentMatch[p.ID]
+		if !ok {
+			continue
+		}
+		for j, t := range tracks {
+			if t.ID == prevID && !usedTracks[j] && iou[i][j] >= ma.iouThreshold {
+				matched[i] = j
+				usedTracks[j] = true
+				break
+			}
+		}
+	}
+
+	// 2. Atribuição ótima para o restante
+	var freeGT, freeTracks []int
+	for i := range people {
+		if _, ok := matched[i]; !ok {
+			freeGT = append(freeGT, i)
+		}
+	}
+	for j := range tracks {
+		if !usedTracks[j] {
+			freeTracks = append(freeTracks, j)
+		}
+	}
+	if len(freeGT) > 0 && len(freeTracks) > 0 {
+		cost := make([][]float64, len(freeGT))
+		for r, i := range freeGT {
+			cost[r] = make([]float64, len(freeTracks))
+			for c, j := range freeTracks {
+				cost[r][c] = math.Inf(1)
+				if iou[i][j] >= ma.iouThreshold {
+					cost[r][c] = 1 - iou[i][j]
+				}
+			}
+		}
+		for r, c := range tracking.Assign(cost) {
+			if c >= 0 {
+				matched[freeGT[r]] = freeTracks[c]
+			}
+		}
+	}
+
+	// 3. Contabiliza casamentos, trocas de identidade, perdas e falsos positivos
+	ma.currentMatch = make(map[int]int, len(matched))
+	for i, j := range matched {
+		gtID, trackID := people[i].ID, tracks[j].ID
+		if last, ok := ma.lastMatch[gtID]; ok && last != trackID {
+			ma.idSwitches++
+		}
+		ma.lastMatch[gtID] = trackID
+		ma.currentMatch[gtID] = trackID
+		ma.matches++
+		ma.iouSum += iou[i][j]
+	}
+	ma.misses += len(people) - len(matched)
+	ma.falsePos += len(tracks) - len(matched)
+}
+
+// TrackingMetrics resume a qualidade do tracking
+type TrackingMetrics struct {
+	GroundTruth    int     `json:"ground_truth"`
+	Misses         int     `json:"misses"`
+	FalsePositives int     `json:"false_positives"`
+	IDSwitches     int     `json:"id_switches"`
+	MOTA           float64 `json:"mota"`
+	MOTP           float64 `json:"motp"` // IoU médio dos casamentos
+	IDF1           float64 `json:"idf1"`
+	IDPrecision    float64 `json:"id_precision"`
+	IDRecall       float64 `json:"id_recall"`
+}
+
+// metrics calcula MOTA, MOTP e IDF1
+func (ma *motAccumulator) metrics() TrackingMetrics {
+	m := TrackingMetrics{
+		GroundTruth:    ma.groundTruth,
+		Misses:         ma.misses,
+		FalsePositives: ma.falsePos,
+		IDSwitches:     ma.idSwitches,
+	}
+	if ma.groundTruth > 0 {
+		m.MOTA = 1 - float64(ma.misses+ma.falsePos+ma.idSwitches)/float64(ma.groundTruth)
+	}
+	if ma.matches > 0 {
+		m.MOTP = ma.iouSum / float64(ma.matches)
+	}
+
+	idtp := ma.identityTruePositives()
+	if ma.predictedBoxes > 0 {
+		m.IDPrecision = float64(idtp) / float64(ma.predictedBoxes)
+	}
+	if ma.groundTruth > 0 {
+		m.IDRecall = float64(idtp) / float64(ma.groundTruth)
+	}
+	if total := ma.groundTruth + ma.predictedBoxes; total > 0 {
+		m.IDF1 = 2 * float64(idtp) / float64(total)
+	}
+	return m
+}
+
+// identityTruePositives casa identidades de ground-truth e trilhas uma a uma,
+// maximizando o número de frames em que o par se sobrepõe
+func (ma *motAccumulator) identityTruePositives() int {
+	gtIDs := make([]int, 0, len(ma.gtFrames))
+	for id := range ma.gtFrames {
+		gtIDs = append(gtIDs, id)
+	}
+	predIDs := make([]int, 0, len(ma.predFrames))
+	for id := range ma.predFrames {
+		predIDs = append(predIDs, id)
+	}
+	if len(gtIDs) == 0 || len(predIDs) == 0 {
+		return 0
+	}
+
+	cost := make([][]float64, len(gtIDs))
+	for r, gtID := range gtIDs {
+		cost[r] = make([]float64, len(predIDs))
+		for c, predID := range predIDs {
+			cost[r][c] = -float64(ma.pairOverlaps[idPair{gtID, predID}])
+		}
+	}
+
+	idtp := 0
+	for r, c := range tracking.Assign(cost) {
+		if c >= 0 {
+			idtp += ma.pairOverlaps[idPair{gtIDs[r], predIDs[c]}]
+		}
+	}
+	return idtp
+}
@@ -0,0 +1,64 @@
+package evaluation
+
+import (
+	"image"
+	"testing"
+)
+
+func TestMOTMetrics(t *testing.T) {
+	a := Object{ID: 1, ClassID: 0, Box: [4]int{0, 0, 50, 100}}
+	b := Object{ID: 2, ClassID: 0, Box: [4]int{300, 0, 350, 100}}
+	track := func(id int, obj Object) TrackBox { return TrackBox{ID: id, Box: obj.Rect()} }
+
+	ma := newMOTAccumulator(0.5)
+	// Frames 1-2: pessoa 1 seguida pela trilha 10
+	ma.add([]Object{a}, []TrackBox{track(10, a)})
+	ma.add([]Object{a}, []TrackBox{track(10, a)})
+	// Frame 3: a trilha troca para 11 (troca de ID) e surge a trilha 12 sem
+	// ninguém (falso positivo)
+	ma.add([]Object{a}, []TrackBox{track(11, a), {ID: 12, Box: image.Rect(600, 0, 650, 100)}})
+	// Frame 4: a pessoa 2 entra e não é rastreada (perda)
+	ma.add([]Object{a, b}, []TrackBox{track(11, a)})
+	// Frame 5: trilha 11 continua na pessoa 1
+	ma.add([]Object{a}, []TrackBox{track(11, a)})
+
+	got := ma.metrics()
+
+	// 6 objetos anotados; 1 perda + 1 FP + 1 troca → MOTA = 1 - 3/6
+	want := TrackingMetrics{GroundTruth: 6, Misses: 1, FalsePositives: 1, IDSwitches: 1, MOTA: 0.5, MOTP: 1}
+	if got.GroundTruth != want.GroundTruth || got.Misses != want.Misses ||
+		got.FalsePositives != want.FalsePositives || got.IDSwitches != want.IDSwitches {
+		t.Errorf("contagens = %+v, esperado %+v", got, want)
+	}
+	if !approxEqual(got.MOTA, want.MOTA) || !approxEqual(got.MOTP, want.MOTP) {
+		t.Errorf("MOTA/MOTP = %.3f/%.3f, esperado %.3f/%.3f", got.MOTA, got.MOTP, want.MOTA, want.MOTP)
+	}
+
+	// IDF1: a pessoa 1 casa com a trilha 11 (3 frames); a trilha 10 (2 frames)
+	// e a pessoa 2 ficam sem par. IDTP = 3 sobre 6 anotados e 6 preditos.
+	if !approxEqual(got.IDPrecision, 0.5) || !approxEqual(got.IDRecall, 0.5) || !approxEqual(got.IDF1, 0.5) {
+		t.Errorf("IDP/IDR/IDF1 = %.3f/%.3f/%.3f, esperado 0.5/0.5/0.5", got.IDPrecision, got.IDRecall, got.IDF1)
+	}
+}
+
+func TestMOTKeepsPreviousMatch(t *testing.T) {
+	// Duas trilhas cobrem a pessoa; a que já estava casada é mantida mesmo
+	// com IoU menor, sem contar troca de ID
+	person := Object{ID: 1, ClassID: 0, Box: [4]int{0, 0, 50, 100}}
+
+	ma := newMOTAccumulator(0.5)
+	ma.add([]Object{person}, []TrackBox{{ID: 7, Box: image.Rect(0, 10, 50, 100)}})
+	ma.add([]Object{person}, []TrackBox{
+		{ID: 7, Box: image.Rect(0, 10, 50, 100)},
+		{ID: 8, Box: person.Rect()},
+	})
+
+	got := ma.metrics()
+	if got.IDSwitches != 0 || got.FalsePositives != 1 {
+		t.Errorf("trocas/FP = %d/%d, esperado 0/1", got.IDSwitches, got.FalsePositives)
+	}
+	// IoU 0.9 nos dois casamentos
+	if !approxEqual(got.MOTP, 0.9) {
+		t.Errorf("MOTP = %.3f, esperado 0.9", got.MOTP)
+	}
+}
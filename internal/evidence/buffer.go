@@ -0,0 +1,53 @@
+package evidence
+
+import "gocv.io/x/gocv"
+
+// FrameBuffer é um buffer circular com cópias dos frames mais recentes
+type FrameBuffer struct {
+	frames []gocv.Mat
+	start  int // posição do frame mais antigo
+	count  int
+}
+
+// NewFrameBuffer cria um buffer com capacidade para n frames
+func NewFrameBuffer(n int) *FrameBuffer {
+	return &FrameBuffer{frames: make([]gocv.Mat, max(n, 0))}
+}
+
+// Push guarda uma cópia do frame, descartando o mais antigo se estiver cheio
+func (b *FrameBuffer) Push(img gocv.Mat) {
+	if len(b.frames) == 0 {
+		return
+	}
+
+	if b.count == len(b.frames) {
+		b.frames[b.start].Close()
+		b.frames[b.start] = img.Clone()
+		b.start = (b.start + 1) % len(b.frames)
+		return
+	}
+
+	b.frames[(b.start+b.count)%len(b.frames)] = img.Clone()
+	b.count++
+}
+
+// Len retorna a quantidade de frames guardados
+func (b *FrameBuffer) Len() int {
+	return b.count
+}
+
+// Each percorre os frames do mais antigo para o mais recente
+func (b *FrameBuffer) Each(fn func(img gocv.Mat)) {
+	for i := 0; i < b.count; i++ {
+		fn(b.frames[(b.start+i)%len(b.frames)])
+	}
+}
+
+// Close libera todos os frames
+func (b *FrameBuffer) Close() {
+	b.Each(func(img gocv.Mat) {
+		img.Close()
+	})
+	b.start = 0
+	b.count = 0
+}
@@ -0,0 +1,220 @@
+package evidence
+
+import (
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/internal/logging"
+)
+
+// Extensões e codec dos arquivos de evidência
+const (
+	snapshotExt = ".jpg"
+	clipExt     = ".avi"
+	clipCodec   = "MJPG"
+)
+
+// unsafeChars remove caracteres inválidos em nomes de arquivo
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Options configura o Recorder
+type Options struct {
+	Dir            string        // diretório das evidências
+	PreRollFrames  int           // frames anteriores ao alerta incluídos no clipe
+	PostRollFrames int           // frames posteriores ao alerta incluídos no clipe
+	ClipFPS        float64       // taxa de quadros dos clipes
+	MaxFiles       int           // máximo de arquivos no diretório (0 = sem limite)
+	MaxBytes       int64         // tamanho máximo do diretório (0 = sem limite)
+	MaxAge         time.Duration // idade máxima dos arquivos (0 = sem limite)
+}
+
+// Evidence aponta para os arquivos gerados para um alerta
+type Evidence struct {
+	Snapshot string
+	Clip     string
+}
+
+// clip é um clipe em gravação aguardando os frames de pós-alerta
+type clip struct {
+	writer    *gocv.VideoWriter
+	path      string
+	snapshot  string
+	size      image.Point
+	remaining int
+}
+
+// Recorder mantém o buffer de pré-alerta e grava snapshot e clipe por alerta.
+// Enquanto um clipe do mesmo track e tipo estiver gravando, novos alertas
+// reaproveitam a mesma evidência e estendem o pós-alerta.
+type Recorder struct {
+	opts   Options
+	buffer *FrameBuffer
+	clips  map[string]*clip
+}
+
+// NewRecorder cria o diretório de evidências e o buffer de frames
+func NewRecorder(opts Options) (*Recorder, error) {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de evidências: %v", err)
+	}
+
+	r := &Recorder{
+		opts:   opts,
+		buffer: NewFrameBuffer(opts.PreRollFrames),
+		clips:  make(map[string]*clip),
+	}
+	r.enforceRetention(time.Now())
+	return r, nil
+}
+
+// Push registra o frame anotado atual: alimenta o buffer de pré-alerta e os clipes em gravação
+func (r *Recorder) Push(img gocv.Mat) {
+	for key, c := range r.clips {
+		if frameSize(img) == c.size {
+			if err := c.writer.Write(img); err != nil {
+				slog.Warn("Erro ao gravar clipe", slog.String("path", c.path), logging.Err(err))
+			}
+		}
+		c.remaining--
+		if c.remaining <= 0 {
+			r.finish(key, c)
+		}
+	}
+
+	r.buffer.Push(img)
+}
+
+// Capture grava o snapshot do frame anotado e inicia o clipe do alerta.
+// O clipe começa com os frames do buffer e continua pelos próximos
+// PostRollFrames frames recebidos em Push.
+func (r *Recorder) Capture(personID int, behaviorType string, img gocv.Mat, timestamp time.Time) (Evidence, error) {
+	key := fmt.Sprintf("%d_%s", personID, behaviorType)
+	if c, recording := r.clips[key]; recording {
+		c.remaining = r.opts.PostRollFrames
+		return Evidence{Snapshot: c.snapshot, Clip: c.path}, nil
+	}
+
+	base := filepath.Join(r.opts.Dir, fmt.Sprintf("%s_track%d_%s",
+		timestamp.Format("20060102-150405.000"), personID, unsafeChars.ReplaceAllString(behaviorType, "_")))
+
+	var ev Evidence
+	snapshot := base + snapshotExt
+	if !gocv.IMWrite(snapshot, img) {
+		return ev, fmt.Errorf("erro ao gravar snapshot %s", snapshot)
+	}
+	ev.Snapshot = snapshot
+
+	if r.opts.PostRollFrames <= 0 && r.buffer.Len() == 0 {
+		return ev, nil
+	}
+
+	size := frameSize(img)
+	path := base + clipExt
+	writer, err := gocv.VideoWriterFile(path, clipCodec, r.opts.ClipFPS, size.X, size.Y, true)
+	if err != nil {
+		return ev, fmt.Errorf("erro ao criar clipe %s: %v", path, err)
+	}
+
+	// Pré-alerta (o buffer já contém o frame atual)
+	r.buffer.Each(func(frame gocv.Mat) {
+		if frameSize(frame) == size {
+			writer.Write(frame)
+		}
+	})
+
+	c := &clip{writer: writer, path: path, snapshot: snapshot, size: size, remaining: r.opts.PostRollFrames}
+	ev.Clip = path
+	if c.remaining <= 0 {
+		r.finish(key, c)
+		return ev, nil
+	}
+	r.clips[key] = c
+	return ev, nil
+}
+
+// Close finaliza os clipes em gravação e libera o buffer
+func (r *Recorder) Close() {
+	for key, c := range r.clips {
+		r.finish(key, c)
+	}
+	r.buffer.Close()
+}
+
+// finish fecha o clipe e aplica os limites de retenção
+func (r *Recorder) finish(key string, c *clip) {
+	c.writer.Close()
+	delete(r.clips, key)
+	r.enforceRetention(time.Now())
+}
+
+// enforceRetention remove as evidências mais antigas acima dos limites de quantidade, tamanho e idade
+func (r *Recorder) enforceRetention(now time.Time) {
+	entries, err := os.ReadDir(r.opts.Dir)
+	if err != nil {
+		return
+	}
+
+	active := make(map[string]bool, len(r.clips)*2)
+	for _, c := range r.clips {
+		active[c.path] = true
+		active[c.snapshot] = true
+	}
+
+	type evidenceFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []evidenceFile
+	var total int64
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != snapshotExt && ext != clipExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(r.opts.Dir, entry.Name())
+		files = append(files, evidenceFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	// Mais antigos primeiro
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	count := len(files)
+	for _, f := range files {
+		tooMany := r.opts.MaxFiles > 0 && count > r.opts.MaxFiles
+		tooBig := r.opts.MaxBytes > 0 && total > r.opts.MaxBytes
+		tooOld := r.opts.MaxAge > 0 && now.Sub(f.modTime) > r.opts.MaxAge
+		if !tooMany && !tooBig && !tooOld {
+			break
+		}
+		if active[f.path] {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			slog.Warn("Erro ao remover evidência antiga", slog.String("path", f.path), logging.Err(err))
+			continue
+		}
+		count--
+		total -= f.size
+	}
+}
+
+// frameSize retorna largura e altura do frame
+func frameSize(img gocv.Mat) image.Point {
+	return image.Pt(img.Cols(), img.Rows())
+}
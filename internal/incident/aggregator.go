@@ -0,0 +1,143 @@
+package incident
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"poc-camera/internal/alert"
+)
+
+// AggregatorOptions configura o ciclo de vida dos incidentes
+type AggregatorOptions struct {
+	CloseAfter     time.Duration // sem observações por esse tempo, o incidente fecha
+	UpdateInterval time.Duration // intervalo mínimo entre eventos updated do mesmo incidente
+}
+
+// occurrence identifica um comportamento de uma trilha em uma câmera
+type occurrence struct {
+	camera   string
+	track    int
+	behavior string
+}
+
+// activeIncident é o estado de um incidente aberto
+type activeIncident struct {
+	id           string
+	startedAt    time.Time
+	lastSeen     time.Time
+	lastEvent    time.Time
+	peak         alert.Alert // observação de maior confiança
+	last         alert.Alert // observação mais recente
+	reportedPeak float32     // confiança máxima já enviada em um evento
+}
+
+// Aggregator transforma as observações por frame de cada comportamento em
+// incidentes com ciclo de vida: um evento opened na primeira observação,
+// updated quando a confiança máxima sobe (no máximo um por UpdateInterval)
+// e closed quando o comportamento some por CloseAfter. Não é seguro para uso
+// concorrente; cada câmera tem o seu.
+type Aggregator struct {
+	opts   AggregatorOptions
+	active map[occurrence]*activeIncident
+	seq    int
+}
+
+// NewAggregator cria o agregador sem incidentes abertos
+func NewAggregator(opts AggregatorOptions) *Aggregator {
+	return &Aggregator{opts: opts, active: make(map[occurrence]*activeIncident)}
+}
+
+// Observe registra uma observação (Type, PersonID, CameraID, Confidence,
+// Timestamp...) e retorna o evento opened ou updated, se houver
+func (a *Aggregator) Observe(obs alert.Alert) (alert.Alert, bool) {
+	key := occurrence{camera: obs.CameraID, track: obs.PersonID, behavior: obs.Type}
+	inc, ok := a.active[key]
+	if !ok {
+		a.seq++
+		inc = &activeIncident{
+			id:           fmt.Sprintf("%s-%d-%d", obs.CameraID, obs.Timestamp.UnixMilli(), a.seq),
+			startedAt:    obs.Timestamp,
+			lastSeen:     obs.Timestamp,
+			lastEvent:    obs.Timestamp,
+			peak:         obs,
+			last:         obs,
+			reportedPeak: obs.Confidence,
+		}
+		a.active[key] = inc
+		return inc.event(alert.EventOpened, obs), true
+	}
+
+	inc.lastSeen = obs.Timestamp
+	inc.last = obs
+	if obs.Confidence > inc.peak.Confidence {
+		inc.peak = obs
+	}
+	if inc.peak.Confidence > inc.reportedPeak && obs.Timestamp.Sub(inc.lastEvent) >= a.opts.UpdateInterval {
+		inc.lastEvent = obs.Timestamp
+		inc.reportedPeak = inc.peak.Confidence
+		return inc.event(alert.EventUpdated, obs), true
+	}
+	return alert.Alert{}, false
+}
+
+// IncidentID retorna o ID do incidente aberto da observação (vazio se não
+// houver), para ligar a observação ao incidente no Store
+func (a *Aggregator) IncidentID(obs alert.Alert) string {
+	if inc, ok := a.active[occurrence{camera: obs.CameraID, track: obs.PersonID, behavior: obs.Type}]; ok {
+		return inc.id
+	}
+	return ""
+}
+
+// Expire fecha os incidentes sem observações há mais de CloseAfter
+func (a *Aggregator) Expire(now time.Time) []alert.Alert {
+	return a.close(func(inc *activeIncident) bool {
+		return now.Sub(inc.lastSeen) > a.opts.CloseAfter
+	})
+}
+
+// Flush fecha todos os incidentes abertos (fim da fonte ou encerramento)
+func (a *Aggregator) Flush() []alert.Alert {
+	return a.close(func(*activeIncident) bool { return true })
+}
+
+// Active retorna quantos incidentes estão abertos
+func (a *Aggregator) Active() int {
+	return len(a.active)
+}
+
+// close fecha os incidentes escolhidos, em ordem de início
+func (a *Aggregator) close(done func(*activeIncident) bool) []alert.Alert {
+	var events []alert.Alert
+	for key, inc := range a.active {
+		if done(inc) {
+			events = append(events, inc.event(alert.EventClosed, inc.last))
+			delete(a.active, key)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartedAt.Equal(events[j].StartedAt) {
+			return events[i].StartedAt.Before(events[j].StartedAt)
+		}
+		return events[i].IncidentID < events[j].IncidentID
+	})
+	return events
+}
+
+// event monta o alerta do evento: dados da observação atual (posição, frame),
+// descrição e confiança do pico
+func (inc *activeIncident) event(kind string, current alert.Alert) alert.Alert {
+	ev := current
+	ev.Event = kind
+	ev.IncidentID = inc.id
+	ev.StartedAt = inc.startedAt
+	ev.DurationSeconds = inc.lastSeen.Sub(inc.startedAt).Seconds()
+	ev.PeakConfidence = inc.peak.Confidence
+	ev.Description = inc.peak.Description
+	ev.Details = inc.peak.Details
+	if kind == alert.EventClosed {
+		ev.Timestamp = inc.lastSeen
+	}
+	return ev
+}
@@ -0,0 +1,89 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"poc-camera/internal/alert"
+)
+
+func TestAggregatorLifecycle(t *testing.T) {
+	agg := NewAggregator(AggregatorOptions{CloseAfter: 2 * time.Second, UpdateInterval: time.Second})
+	base := time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)
+
+	// 3s de observações a 10 fps da mesma trilha; a confiança sobe até 0.9
+	var events []alert.Alert
+	for i := 0; i < 30; i++ {
+		now := base.Add(time.Duration(i) * 100 * time.Millisecond)
+		conf := float32(0.5)
+		if i >= 5 {
+			conf = 0.5 + float32(i-5)*0.02
+		}
+		conf = min(conf, 0.9)
+		obs := alert.Alert{CameraID: "cam0", PersonID: 7, Type: "PROXIMIDADE_SUSPEITA", Confidence: conf, Timestamp: now}
+		if ev, ok := agg.Observe(obs); ok {
+			events = append(events, ev)
+		}
+		// Outro comportamento da mesma trilha é outro incidente
+		if i == 10 {
+			obs.Type = "PERMANENCIA_EXCESSIVA"
+			if ev, ok := agg.Observe(obs); ok {
+				events = append(events, ev)
+			}
+		}
+		events = append(events, agg.Expire(now)...)
+	}
+
+	var opened, updated int
+	for _, ev := range events {
+		switch ev.Event {
+		case alert.EventOpened:
+			opened++
+		case alert.EventUpdated:
+			updated++
+			if ev.PeakConfidence <= 0.5 {
+				t.Errorf("update sem aumento de confiança: %+v", ev)
+			}
+		default:
+			t.Errorf("evento inesperado antes do fim das observações: %q", ev.Event)
+		}
+	}
+	if opened != 2 {
+		t.Errorf("incidentes abertos = %d, esperado 2", opened)
+	}
+	// Confiança subindo por ~2.5s com intervalo mínimo de 1s
+	if updated < 1 || updated > 3 {
+		t.Errorf("updates = %d, esperado entre 1 e 3", updated)
+	}
+	if agg.Active() != 2 {
+		t.Errorf("incidentes ativos = %d, esperado 2", agg.Active())
+	}
+
+	// PERMANENCIA_EXCESSIVA foi vista só uma vez (1s): fecha antes
+	last := base.Add(2900 * time.Millisecond)
+	closed := agg.Expire(base.Add(3500 * time.Millisecond))
+	if len(closed) != 1 || closed[0].Type != "PERMANENCIA_EXCESSIVA" {
+		t.Fatalf("fechados = %+v, esperado só PERMANENCIA_EXCESSIVA", closed)
+	}
+
+	closed = agg.Flush()
+	if len(closed) != 1 {
+		t.Fatalf("flush fechou %d incidentes, esperado 1", len(closed))
+	}
+	ev := closed[0]
+	switch {
+	case ev.Event != alert.EventClosed:
+		t.Errorf("evento = %q, esperado closed", ev.Event)
+	case ev.IncidentID != events[0].IncidentID:
+		t.Errorf("ID do incidente mudou: %q → %q", events[0].IncidentID, ev.IncidentID)
+	case !ev.StartedAt.Equal(base) || !ev.Timestamp.Equal(last):
+		t.Errorf("início %v, fim %v", ev.StartedAt, ev.Timestamp)
+	case ev.DurationSeconds != 2.9:
+		t.Errorf("duração = %gs, esperado 2.9s", ev.DurationSeconds)
+	case ev.PeakConfidence < 0.89:
+		t.Errorf("confiança máxima = %v, esperado 0.9", ev.PeakConfidence)
+	}
+	if agg.Active() != 0 {
+		t.Errorf("incidentes ativos após flush = %d", agg.Active())
+	}
+}
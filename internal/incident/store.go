@@ -0,0 +1,571 @@
+// Package incident agrega as observações por frame dos comportamentos
+// suspeitos em incidentes com ciclo de vida (aberto, atualizado, encerrado) e
+// persiste observações, eventos e incidentes em um banco SQLite embutido.
+package incident
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // driver sqlite3
+	"poc-camera/internal/alert"
+)
+
+// schemaVersion é a versão do esquema gravada em PRAGMA user_version.
+// Versões anteriores não gravavam user_version e são reconhecidas pelas
+// colunas (ver legacyVersion): 1 agrupava alertas por trilha com
+// alert_count; 2 guardava só os eventos do ciclo de vida em behaviors.
+const schemaVersion = 3
+
+// schema cria as tabelas na primeira abertura. Horários são gravados em
+// milissegundos Unix (UTC) para permitir consultas por intervalo.
+const schema = `
+CREATE TABLE IF NOT EXISTS incidents (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	incident_key    TEXT    NOT NULL UNIQUE,
+	camera_id       TEXT    NOT NULL,
+	track_id        INTEGER NOT NULL,
+	type            TEXT    NOT NULL,
+	started_at      INTEGER NOT NULL,
+	last_seen_at    INTEGER NOT NULL,
+	ended_at        INTEGER,
+	peak_confidence REAL    NOT NULL,
+	events          INTEGER NOT NULL,
+	description     TEXT    NOT NULL,
+	details         TEXT    NOT NULL,
+	snapshot_path   TEXT    NOT NULL,
+	clip_path       TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS incidents_camera_time ON incidents (camera_id, started_at);
+
+CREATE TABLE IF NOT EXISTS behaviors (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	incident_id   INTEGER NOT NULL REFERENCES incidents (id),
+	camera_id     TEXT    NOT NULL,
+	source        TEXT    NOT NULL,
+	type          TEXT    NOT NULL,
+	confidence    REAL    NOT NULL,
+	track_id      INTEGER NOT NULL,
+	location_x    INTEGER NOT NULL,
+	location_y    INTEGER NOT NULL,
+	description   TEXT    NOT NULL,
+	details       TEXT    NOT NULL,
+	frame         INTEGER NOT NULL,
+	occurred_at   INTEGER NOT NULL,
+	snapshot_path TEXT    NOT NULL,
+	clip_path     TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS behaviors_incident ON behaviors (incident_id);
+CREATE INDEX IF NOT EXISTS behaviors_camera_time ON behaviors (camera_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS incident_events (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	incident_id     INTEGER NOT NULL REFERENCES incidents (id),
+	event           TEXT    NOT NULL,
+	confidence      REAL    NOT NULL,
+	peak_confidence REAL    NOT NULL,
+	location_x      INTEGER NOT NULL,
+	location_y      INTEGER NOT NULL,
+	description     TEXT    NOT NULL,
+	details         TEXT    NOT NULL,
+	frame           INTEGER NOT NULL,
+	occurred_at     INTEGER NOT NULL,
+	snapshot_path   TEXT    NOT NULL,
+	clip_path       TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS incident_events_incident ON incident_events (incident_id);
+`
+
+// migrateV1 converte o esquema da versão 1: cada incidente antigo vira um
+// incidente encerrado por tipo de comportamento, com os alertas como
+// observações
+const migrateV1 = `
+DROP INDEX incidents_camera_time;
+DROP INDEX behaviors_incident;
+DROP INDEX behaviors_camera_time;
+ALTER TABLE incidents RENAME TO incidents_v1;
+ALTER TABLE behaviors RENAME TO behaviors_v1;
+` + schema + `
+INSERT INTO incidents (incident_key, camera_id, track_id, type, started_at, last_seen_at, ended_at,
+	peak_confidence, events, description, details, snapshot_path, clip_path)
+SELECT 'v1-' || i.id || '-' || b.type, i.camera_id, i.track_id, b.type,
+	min(b.occurred_at), max(b.occurred_at), max(b.occurred_at), max(b.confidence), 0, '', '', '', ''
+FROM incidents_v1 i JOIN behaviors_v1 b ON b.incident_id = i.id
+GROUP BY i.id, b.type;
+
+INSERT INTO behaviors (incident_id, camera_id, source, type, confidence, track_id, location_x, location_y,
+	description, details, frame, occurred_at, snapshot_path, clip_path)
+SELECT n.id, b.camera_id, b.source, b.type, b.confidence, b.track_id, b.location_x, b.location_y,
+	b.description, b.details, b.frame, b.occurred_at, b.snapshot_path, b.clip_path
+FROM behaviors_v1 b JOIN incidents n ON n.incident_key = 'v1-' || b.incident_id || '-' || b.type
+ORDER BY b.id;
+
+UPDATE incidents SET
+	description   = (SELECT description FROM behaviors WHERE incident_id = incidents.id ORDER BY confidence DESC, id LIMIT 1),
+	details       = (SELECT details FROM behaviors WHERE incident_id = incidents.id ORDER BY confidence DESC, id LIMIT 1),
+	snapshot_path = coalesce((SELECT snapshot_path FROM behaviors WHERE incident_id = incidents.id AND snapshot_path != '' ORDER BY id DESC LIMIT 1), ''),
+	clip_path     = coalesce((SELECT clip_path FROM behaviors WHERE incident_id = incidents.id AND clip_path != '' ORDER BY id DESC LIMIT 1), '');
+
+DROP TABLE behaviors_v1;
+DROP TABLE incidents_v1;
+`
+
+// migrateV2 move os eventos do ciclo de vida, que a versão 2 guardava em
+// behaviors, para incident_events (a versão 2 não tinha observações)
+const migrateV2 = `
+DROP INDEX behaviors_incident;
+DROP INDEX behaviors_camera_time;
+ALTER TABLE behaviors RENAME TO behaviors_v2;
+` + schema + `
+INSERT INTO incident_events (incident_id, event, confidence, peak_confidence, location_x, location_y,
+	description, details, frame, occurred_at, snapshot_path, clip_path)
+SELECT incident_id, event, confidence, confidence, location_x, location_y,
+	description, details, frame, occurred_at, snapshot_path, clip_path
+FROM behaviors_v2 ORDER BY id;
+
+DROP TABLE behaviors_v2;
+`
+
+// migrations leva cada versão anterior ao esquema atual (0 = banco vazio)
+var migrations = map[int]string{
+	0: schema,
+	1: migrateV1,
+	2: migrateV2,
+}
+
+// upsertIncident cria o incidente no primeiro evento recebido (normalmente
+// opened) e o atualiza nos seguintes
+const upsertIncident = `
+INSERT INTO incidents (incident_key, camera_id, track_id, type, started_at, last_seen_at, ended_at,
+	peak_confidence, events, description, details, snapshot_path, clip_path)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?)
+ON CONFLICT (incident_key) DO UPDATE SET
+	last_seen_at    = max(last_seen_at, excluded.last_seen_at),
+	ended_at        = coalesce(excluded.ended_at, ended_at),
+	peak_confidence = max(peak_confidence, excluded.peak_confidence),
+	events          = events + 1,
+	description     = excluded.description,
+	details         = excluded.details,
+	snapshot_path   = CASE WHEN excluded.snapshot_path != '' THEN excluded.snapshot_path ELSE snapshot_path END,
+	clip_path       = CASE WHEN excluded.clip_path != '' THEN excluded.clip_path ELSE clip_path END`
+
+// observeIncident garante o incidente de uma observação (que pode chegar
+// antes do evento opened) sem contar um evento
+const observeIncident = `
+INSERT INTO incidents (incident_key, camera_id, track_id, type, started_at, last_seen_at, ended_at,
+	peak_confidence, events, description, details, snapshot_path, clip_path)
+VALUES (?, ?, ?, ?, ?, ?, NULL, ?, 0, ?, ?, '', '')
+ON CONFLICT (incident_key) DO UPDATE SET
+	last_seen_at    = max(last_seen_at, excluded.last_seen_at),
+	peak_confidence = max(peak_confidence, excluded.peak_confidence)`
+
+// Incident é uma ocorrência de um comportamento de uma trilha, do primeiro
+// ao último frame em que foi observado
+type Incident struct {
+	ID             int64
+	Key            string // IncidentID dos eventos (alert.Alert)
+	CameraID       string
+	TrackID        int
+	Type           string
+	StartedAt      time.Time
+	LastSeenAt     time.Time
+	EndedAt        time.Time // zero enquanto aberto
+	PeakConfidence float32
+	Events         int    // eventos do ciclo de vida recebidos
+	Description    string // do momento de maior confiança
+	Details        string
+	SnapshotPath   string
+	ClipPath       string
+}
+
+// Open informa se o incidente ainda não foi encerrado
+func (i Incident) Open() bool {
+	return i.EndedAt.IsZero()
+}
+
+// Duration retorna o tempo entre a primeira e a última observação
+func (i Incident) Duration() time.Duration {
+	return i.LastSeenAt.Sub(i.StartedAt)
+}
+
+// Behavior é uma observação persistida de um comportamento (alert.Alert sem
+// Event), ligada ao seu incidente
+type Behavior struct {
+	ID           int64
+	IncidentID   int64
+	CameraID     string
+	Source       string
+	Type         string
+	Confidence   float32
+	TrackID      int
+	Location     alert.Point
+	Description  string
+	Details      string
+	Frame        int
+	OccurredAt   time.Time
+	SnapshotPath string
+	ClipPath     string
+}
+
+// Event é um evento persistido do ciclo de vida de um incidente
+type Event struct {
+	ID             int64
+	IncidentID     int64
+	Event          string // opened, updated ou closed
+	Confidence     float32
+	PeakConfidence float32
+	Location       alert.Point
+	Description    string
+	Details        string
+	Frame          int
+	OccurredAt     time.Time
+	SnapshotPath   string
+	ClipPath       string
+}
+
+// Query filtra consultas; campos vazios não filtram. From/To formam o
+// intervalo [From, To): para incidentes, vale qualquer sobreposição.
+type Query struct {
+	From     time.Time
+	To       time.Time
+	CameraID string
+	Type     string
+	Limit    int // 0 = sem limite
+}
+
+// Store grava observações e eventos de incidentes e os consulta.
+// Implementa alert.Sink.
+type Store struct {
+	mu sync.Mutex
+	db *sql.DB
+}
+
+// Open abre (ou cria) o banco em path, migrando esquemas de versões
+// anteriores
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir banco de incidentes: %v", err)
+	}
+	// Um único escritor evita "database is locked" (e mantém :memory: em
+	// uma só conexão)
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// migrate cria as tabelas ou atualiza o esquema até schemaVersion
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("erro ao ler versão do banco de incidentes: %v", err)
+	}
+	if version > schemaVersion {
+		return fmt.Errorf("banco de incidentes na versão %d, mais nova que a suportada (%d)", version, schemaVersion)
+	}
+	if version == schemaVersion {
+		return nil
+	}
+	if version == 0 {
+		var err error
+		if version, err = legacyVersion(db); err != nil {
+			return err
+		}
+	}
+	steps, ok := migrations[version]
+	if !ok {
+		return fmt.Errorf("banco de incidentes na versão %d, sem migração para a versão %d", version, schemaVersion)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao migrar banco de incidentes: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(steps); err != nil {
+		return fmt.Errorf("erro ao migrar banco de incidentes para a versão %d: %v", schemaVersion, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion)); err != nil {
+		return fmt.Errorf("erro ao migrar banco de incidentes: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao migrar banco de incidentes: %v", err)
+	}
+	return nil
+}
+
+// legacyVersion reconhece a versão de bancos sem user_version pelas colunas:
+// 1 (incidents sem incident_key), 2 (behaviors com event) ou 0 (vazio)
+func legacyVersion(db *sql.DB) (int, error) {
+	columns := func(table string) (map[string]bool, error) {
+		rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler esquema do banco de incidentes: %v", err)
+		}
+		defer rows.Close()
+		names := make(map[string]bool)
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return nil, fmt.Errorf("erro ao ler esquema do banco de incidentes: %v", err)
+			}
+			names[name] = true
+		}
+		return names, rows.Err()
+	}
+
+	incidents, err := columns("incidents")
+	if err != nil {
+		return 0, err
+	}
+	behaviors, err := columns("behaviors")
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case len(incidents) > 0 && !incidents["incident_key"]:
+		return 1, nil
+	case behaviors["event"]:
+		return 2, nil
+	}
+	// Vazio (ou já no esquema atual: as criações são idempotentes)
+	return 0, nil
+}
+
+// Send implementa alert.Sink: grava a observação ou o evento e atualiza o
+// incidente
+func (s *Store) Send(a alert.Alert) error {
+	_, err := s.Record(a)
+	return err
+}
+
+// Record grava um alert.Alert com IncidentID e retorna o ID do incidente no
+// banco. Sem Event, é uma observação do comportamento (tabela behaviors);
+// com Event, um evento do ciclo de vida (tabela incident_events).
+func (s *Store) Record(a alert.Alert) (int64, error) {
+	if a.IncidentID == "" {
+		return 0, fmt.Errorf("erro ao gravar alerta: alerta sem incident_id")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar alerta: %v", err)
+	}
+	defer tx.Rollback()
+
+	at := a.Timestamp.UnixMilli()
+	if a.Event == "" {
+		_, err = tx.Exec(observeIncident, a.IncidentID, a.CameraID, a.PersonID, a.Type, at, at,
+			a.Confidence, a.Description, a.Details)
+	} else {
+		var endedAt sql.NullInt64
+		if a.Event == alert.EventClosed {
+			endedAt = sql.NullInt64{Int64: at, Valid: true}
+		}
+		started := a.StartedAt
+		if started.IsZero() {
+			started = a.Timestamp
+		}
+		peak := max(a.PeakConfidence, a.Confidence)
+		_, err = tx.Exec(upsertIncident, a.IncidentID, a.CameraID, a.PersonID, a.Type, started.UnixMilli(), at,
+			endedAt, peak, a.Description, a.Details, a.SnapshotPath, a.ClipPath)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar incidente: %v", err)
+	}
+
+	var id int64
+	if err := tx.QueryRow(`SELECT id FROM incidents WHERE incident_key = ?`, a.IncidentID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("erro ao gravar incidente: %v", err)
+	}
+
+	if a.Event == "" {
+		_, err = tx.Exec(`INSERT INTO behaviors (incident_id, camera_id, source, type, confidence, track_id,
+			location_x, location_y, description, details, frame, occurred_at, snapshot_path, clip_path)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, a.CameraID, a.Source, a.Type, a.Confidence, a.PersonID,
+			a.Location.X, a.Location.Y, a.Description, a.Details, a.Frame, at, a.SnapshotPath, a.ClipPath)
+	} else {
+		_, err = tx.Exec(`INSERT INTO incident_events (incident_id, event, confidence, peak_confidence,
+			location_x, location_y, description, details, frame, occurred_at, snapshot_path, clip_path)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, a.Event, a.Confidence, max(a.PeakConfidence, a.Confidence),
+			a.Location.X, a.Location.Y, a.Description, a.Details, a.Frame, at, a.SnapshotPath, a.ClipPath)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar alerta: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("erro ao gravar alerta: %v", err)
+	}
+	return id, nil
+}
+
+// Incidents retorna os incidentes que atendem ao filtro, mais recentes primeiro
+func (s *Store) Incidents(q Query) ([]Incident, error) {
+	var where []string
+	var args []any
+	if !q.From.IsZero() {
+		where = append(where, "last_seen_at >= ?")
+		args = append(args, q.From.UnixMilli())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "started_at < ?")
+		args = append(args, q.To.UnixMilli())
+	}
+	if q.CameraID != "" {
+		where = append(where, "camera_id = ?")
+		args = append(args, q.CameraID)
+	}
+	if q.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, q.Type)
+	}
+
+	query := `SELECT id, incident_key, camera_id, track_id, type, started_at, last_seen_at, ended_at,
+		peak_confidence, events, description, details, snapshot_path, clip_path
+		FROM incidents` + whereClause(where) + ` ORDER BY started_at DESC, id DESC` + limitClause(q.Limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar incidentes: %v", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		var inc Incident
+		var started, lastSeen int64
+		var ended sql.NullInt64
+		if err := rows.Scan(&inc.ID, &inc.Key, &inc.CameraID, &inc.TrackID, &inc.Type, &started, &lastSeen, &ended,
+			&inc.PeakConfidence, &inc.Events, &inc.Description, &inc.Details, &inc.SnapshotPath, &inc.ClipPath); err != nil {
+			return nil, fmt.Errorf("erro ao ler incidente: %v", err)
+		}
+		inc.StartedAt = time.UnixMilli(started)
+		inc.LastSeenAt = time.UnixMilli(lastSeen)
+		if ended.Valid {
+			inc.EndedAt = time.UnixMilli(ended.Int64)
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, rows.Err()
+}
+
+// Behaviors retorna as observações que atendem ao filtro, mais recentes primeiro
+func (s *Store) Behaviors(q Query) ([]Behavior, error) {
+	where, args := behaviorFilter(q)
+	query := `SELECT id, incident_id, camera_id, source, type, confidence, track_id, location_x, location_y,
+		description, details, frame, occurred_at, snapshot_path, clip_path
+		FROM behaviors` + whereClause(where) + ` ORDER BY occurred_at DESC, id DESC` + limitClause(q.Limit)
+	return s.queryBehaviors(query, args...)
+}
+
+// IncidentBehaviors retorna as observações de um incidente em ordem cronológica
+func (s *Store) IncidentBehaviors(incidentID int64) ([]Behavior, error) {
+	return s.queryBehaviors(`SELECT id, incident_id, camera_id, source, type, confidence, track_id, location_x, location_y,
+		description, details, frame, occurred_at, snapshot_path, clip_path
+		FROM behaviors WHERE incident_id = ? ORDER BY occurred_at, id`, incidentID)
+}
+
+// queryBehaviors executa uma consulta na tabela behaviors
+func (s *Store) queryBehaviors(query string, args ...any) ([]Behavior, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar alertas: %v", err)
+	}
+	defer rows.Close()
+
+	var behaviors []Behavior
+	for rows.Next() {
+		var b Behavior
+		var occurred int64
+		if err := rows.Scan(&b.ID, &b.IncidentID, &b.CameraID, &b.Source, &b.Type, &b.Confidence, &b.TrackID,
+			&b.Location.X, &b.Location.Y, &b.Description, &b.Details, &b.Frame, &occurred,
+			&b.SnapshotPath, &b.ClipPath); err != nil {
+			return nil, fmt.Errorf("erro ao ler alerta: %v", err)
+		}
+		b.OccurredAt = time.UnixMilli(occurred)
+		behaviors = append(behaviors, b)
+	}
+	return behaviors, rows.Err()
+}
+
+// IncidentEvents retorna os eventos do ciclo de vida de um incidente em ordem
+// cronológica
+func (s *Store) IncidentEvents(incidentID int64) ([]Event, error) {
+	rows, err := s.db.Query(`SELECT id, incident_id, event, confidence, peak_confidence, location_x, location_y,
+		description, details, frame, occurred_at, snapshot_path, clip_path
+		FROM incident_events WHERE incident_id = ? ORDER BY occurred_at, id`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar eventos: %v", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var ev Event
+		var occurred int64
+		if err := rows.Scan(&ev.ID, &ev.IncidentID, &ev.Event, &ev.Confidence, &ev.PeakConfidence,
+			&ev.Location.X, &ev.Location.Y, &ev.Description, &ev.Details, &ev.Frame, &occurred,
+			&ev.SnapshotPath, &ev.ClipPath); err != nil {
+			return nil, fmt.Errorf("erro ao ler evento: %v", err)
+		}
+		ev.OccurredAt = time.UnixMilli(occurred)
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// Close implementa alert.Sink
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// behaviorFilter monta as condições de Query para a tabela behaviors
+func behaviorFilter(q Query) ([]string, []any) {
+	var where []string
+	var args []any
+	if !q.From.IsZero() {
+		where = append(where, "occurred_at >= ?")
+		args = append(args, q.From.UnixMilli())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "occurred_at < ?")
+		args = append(args, q.To.UnixMilli())
+	}
+	if q.CameraID != "" {
+		where = append(where, "camera_id = ?")
+		args = append(args, q.CameraID)
+	}
+	if q.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, q.Type)
+	}
+	return where, args
+}
+
+// whereClause junta as condições com AND
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// limitClause limita o número de linhas (0 = sem limite)
+func limitClause(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
@@ -0,0 +1,307 @@
+package incident
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"poc-camera/internal/alert"
+)
+
+func TestStorePersistsIncidentLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incidentes.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+	event := func(kind, id, camera string, track int, behavior string, conf float32, start, now int) alert.Alert {
+		return alert.Alert{
+			Event: kind, IncidentID: id, CameraID: camera, PersonID: track, Type: behavior,
+			Confidence: conf, PeakConfidence: conf, StartedAt: at(start), Timestamp: at(now),
+		}
+	}
+
+	opened := event(alert.EventOpened, "cam0-a", "cam0", 1, "PERMANENCIA_EXCESSIVA", 0.6, 0, 0)
+	opened.SnapshotPath = "evidencias/a.jpg"
+	opened.Location = alert.Point{X: 10, Y: 20}
+	events := []alert.Alert{
+		opened,
+		event(alert.EventOpened, "cam0-b", "cam0", 2, "PROXIMIDADE_SUSPEITA", 0.7, 5, 5),
+		event(alert.EventUpdated, "cam0-a", "cam0", 1, "PERMANENCIA_EXCESSIVA", 0.8, 0, 12),
+		event(alert.EventOpened, "cam1-a", "cam1", 1, "PERMANENCIA_EXCESSIVA", 0.5, 25, 25),
+		event(alert.EventClosed, "cam0-a", "cam0", 1, "PERMANENCIA_EXCESSIVA", 0.7, 0, 20),
+		event(alert.EventOpened, "cam0-c", "cam0", 1, "PROXIMIDADE_SUSPEITA", 0.9, 60, 60),
+	}
+	for _, ev := range events {
+		if _, err := store.Record(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Observações brutas; a de cam1-b chega sem evento e cria o incidente
+	for _, sec := range []int{0, 1, 2} {
+		obs := event("", "cam0-a", "cam0", 1, "PERMANENCIA_EXCESSIVA", 0.6, 0, sec)
+		obs.Location = alert.Point{X: 10 + sec, Y: 20}
+		if _, err := store.Record(obs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Record(event("", "cam1-b", "cam1", 4, "PROXIMIDADE_SUSPEITA", 0.4, 70, 70)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Record(alert.Alert{Type: "PERMANENCIA_EXCESSIVA"}); err == nil {
+		t.Error("evento sem incident_id deveria falhar")
+	}
+	store.Close()
+
+	// Reabre o arquivo: os dados persistem
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	incidents, err := store.Incidents(Query{CameraID: "cam0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 3 {
+		t.Fatalf("incidentes da cam0 = %d, esperado 3", len(incidents))
+	}
+	first := incidents[2] // mais recentes primeiro
+	switch {
+	case first.Key != "cam0-a" || first.Open() || first.Events != 3:
+		t.Errorf("primeiro incidente = %+v", first)
+	case first.PeakConfidence != 0.8:
+		t.Errorf("confiança máxima = %v, esperado 0.8", first.PeakConfidence)
+	case first.Duration() != 20*time.Second || !first.EndedAt.Equal(at(20)):
+		t.Errorf("duração = %v, fim = %v", first.Duration(), first.EndedAt)
+	case first.SnapshotPath != "evidencias/a.jpg":
+		t.Errorf("snapshot = %q", first.SnapshotPath)
+	}
+	if !incidents[0].Open() {
+		t.Error("incidente sem evento closed deveria continuar aberto")
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{"todos", Query{}, 5},
+		{"por tipo", Query{Type: "PROXIMIDADE_SUSPEITA"}, 3},
+		{"por câmera e tipo", Query{CameraID: "cam1", Type: "PERMANENCIA_EXCESSIVA"}, 1},
+		{"intervalo sobrepondo", Query{From: at(10), To: at(30)}, 2},
+		{"intervalo vazio", Query{From: at(30), To: at(50)}, 0},
+		{"limite", Query{Limit: 2}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Incidents(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("incidentes = %d, esperado %d", len(got), tt.want)
+			}
+		})
+	}
+
+	lifecycle, err := store.IncidentEvents(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lifecycle) != 3 {
+		t.Fatalf("eventos do incidente = %d, esperado 3", len(lifecycle))
+	}
+	if ev := lifecycle[0]; ev.Event != alert.EventOpened || ev.Location != (alert.Point{X: 10, Y: 20}) {
+		t.Errorf("evento persistido = %+v", ev)
+	}
+	if lifecycle[2].Event != alert.EventClosed {
+		t.Errorf("último evento = %q, esperado closed", lifecycle[2].Event)
+	}
+
+	observed, err := store.IncidentBehaviors(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observed) != 3 || observed[2].Location != (alert.Point{X: 12, Y: 20}) {
+		t.Errorf("observações do incidente = %+v, esperado 3", observed)
+	}
+
+	behaviorTests := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{"observações em [0s, 2s)", Query{From: at(0), To: at(2)}, 2},
+		{"observações por tipo", Query{Type: "PROXIMIDADE_SUSPEITA"}, 1},
+		{"observações por câmera", Query{CameraID: "cam0"}, 3},
+	}
+	for _, tt := range behaviorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Behaviors(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("observações = %d, esperado %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+// schemaV1 é o esquema da versão 1 (alertas agrupados por trilha)
+const schemaV1 = `
+CREATE TABLE incidents (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	camera_id   TEXT    NOT NULL,
+	track_id    INTEGER NOT NULL,
+	started_at  INTEGER NOT NULL,
+	ended_at    INTEGER NOT NULL,
+	alert_count INTEGER NOT NULL
+);
+CREATE INDEX incidents_camera_time ON incidents (camera_id, started_at);
+
+CREATE TABLE behaviors (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	incident_id   INTEGER NOT NULL REFERENCES incidents (id),
+	camera_id     TEXT    NOT NULL,
+	source        TEXT    NOT NULL,
+	type          TEXT    NOT NULL,
+	confidence    REAL    NOT NULL,
+	track_id      INTEGER NOT NULL,
+	location_x    INTEGER NOT NULL,
+	location_y    INTEGER NOT NULL,
+	description   TEXT    NOT NULL,
+	details       TEXT    NOT NULL,
+	frame         INTEGER NOT NULL,
+	occurred_at   INTEGER NOT NULL,
+	snapshot_path TEXT    NOT NULL,
+	clip_path     TEXT    NOT NULL
+);
+CREATE INDEX behaviors_incident ON behaviors (incident_id);
+CREATE INDEX behaviors_camera_time ON behaviors (camera_id, occurred_at);
+
+INSERT INTO incidents VALUES (1, 'cam0', 3, 1000, 4000, 3);
+INSERT INTO behaviors VALUES
+	(1, 1, 'cam0', 'câmera 0', 'PERMANENCIA_EXCESSIVA', 0.6, 3, 10, 20, 'Permanência', '', 30, 1000, '', ''),
+	(2, 1, 'cam0', 'câmera 0', 'PROXIMIDADE_SUSPEITA', 0.7, 3, 11, 20, 'Próximo a celular', '', 60, 2000, 'a.jpg', ''),
+	(3, 1, 'cam0', 'câmera 0', 'PERMANENCIA_EXCESSIVA', 0.8, 3, 12, 20, 'Permanência longa', '', 120, 4000, '', '');
+`
+
+func TestStoreMigratesV1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v1.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(schemaV1); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("migração da versão 1: %v", err)
+	}
+	defer store.Close()
+
+	// Um incidente por tipo de comportamento, encerrado na última observação
+	incidents, err := store.Incidents(Query{Type: "PERMANENCIA_EXCESSIVA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("incidentes migrados = %d, esperado 1", len(incidents))
+	}
+	inc := incidents[0]
+	switch {
+	case inc.Open() || inc.Duration() != 3*time.Second:
+		t.Errorf("incidente migrado = %+v", inc)
+	case inc.PeakConfidence != 0.8 || inc.Description != "Permanência longa":
+		t.Errorf("pico migrado = %v %q", inc.PeakConfidence, inc.Description)
+	}
+
+	behaviors, err := store.Behaviors(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(behaviors) != 3 {
+		t.Errorf("observações migradas = %d, esperado 3", len(behaviors))
+	}
+
+	// O banco migrado aceita gravações no esquema novo
+	if _, err := store.Record(alert.Alert{Event: alert.EventOpened, IncidentID: "cam0-x", CameraID: "cam0",
+		Type: "PROXIMIDADE_SUSPEITA", Confidence: 0.5, Timestamp: time.UnixMilli(9000)}); err != nil {
+		t.Errorf("gravação após migração: %v", err)
+	}
+}
+
+func TestStoreRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "futuro.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion+1)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if store, err := Open(path); err == nil {
+		store.Close()
+		t.Error("banco de versão mais nova deveria ser recusado")
+	}
+}
+
+func TestStoreMigratesV2(t *testing.T) {
+	// Versão 2: eventos do ciclo de vida em behaviors, coluna event
+	path := filepath.Join(t.TempDir(), "v2.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(schema + `
+DROP TABLE incident_events;
+DROP TABLE behaviors;
+CREATE TABLE behaviors (
+	id INTEGER PRIMARY KEY AUTOINCREMENT, incident_id INTEGER NOT NULL, event TEXT NOT NULL,
+	camera_id TEXT NOT NULL, source TEXT NOT NULL, type TEXT NOT NULL, confidence REAL NOT NULL,
+	track_id INTEGER NOT NULL, location_x INTEGER NOT NULL, location_y INTEGER NOT NULL,
+	description TEXT NOT NULL, details TEXT NOT NULL, frame INTEGER NOT NULL, occurred_at INTEGER NOT NULL,
+	snapshot_path TEXT NOT NULL, clip_path TEXT NOT NULL
+);
+CREATE INDEX behaviors_incident ON behaviors (incident_id);
+CREATE INDEX behaviors_camera_time ON behaviors (camera_id, occurred_at);
+INSERT INTO incidents VALUES (1, 'cam0-a', 'cam0', 1, 'PROXIMIDADE_SUSPEITA', 0, 5000, 5000, 0.9, 2, '', '', '', '');
+INSERT INTO behaviors VALUES
+	(1, 1, 'opened', 'cam0', '', 'PROXIMIDADE_SUSPEITA', 0.6, 1, 0, 0, '', '', 1, 0, '', ''),
+	(2, 1, 'closed', 'cam0', '', 'PROXIMIDADE_SUSPEITA', 0.9, 1, 0, 0, '', '', 150, 5000, '', '');
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("migração da versão 2: %v", err)
+	}
+	defer store.Close()
+
+	events, err := store.IncidentEvents(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[1].Event != alert.EventClosed {
+		t.Errorf("eventos migrados = %+v", events)
+	}
+	if behaviors, err := store.Behaviors(Query{}); err != nil || len(behaviors) != 0 {
+		t.Errorf("observações após migração = %d (%v), esperado 0", len(behaviors), err)
+	}
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Ícones padrão por nível, quando o registro não define um
+var levelIcons = map[slog.Level]string{
+	slog.LevelDebug: "🔍",
+	slog.LevelWarn:  "⚠️ ",
+	slog.LevelError: "❌",
+}
+
+// ConsoleHandler escreve uma linha legível por registro, no estilo do
+// console original: "<ícone> [câmera] mensagem chave=valor ...". Sem horário
+// nem nível; a câmera vira prefixo e o ícone vem de Icon ou do nível.
+type ConsoleHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr // acumulados por WithAttrs, já com o prefixo do grupo
+	prefix string      // grupos abertos por WithGroup ("a.b.")
+}
+
+// NewConsoleHandler cria o handler escrevendo em w a partir de level
+func NewConsoleHandler(w io.Writer, level slog.Leveler) *ConsoleHandler {
+	return &ConsoleHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+// Enabled implementa slog.Handler
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implementa slog.Handler
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	line := consoleLine{icon: levelIcons[r.Level]}
+	for _, a := range h.attrs {
+		line.add("", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		line.add(h.prefix, a)
+		return true
+	})
+
+	var b strings.Builder
+	if line.icon != "" {
+		b.WriteString(line.icon)
+		b.WriteByte(' ')
+	}
+	if line.camera != "" {
+		b.WriteString("[" + line.camera + "] ")
+	}
+	b.WriteString(r.Message)
+	for _, field := range line.fields {
+		b.WriteByte(' ')
+		b.WriteString(field)
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs implementa slog.Handler
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if h.prefix != "" && a.Key != keyIcon && a.Key != KeyCamera {
+			a.Key = h.prefix + a.Key
+		}
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+// WithGroup implementa slog.Handler
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// consoleLine acumula as partes de uma linha do console
+type consoleLine struct {
+	icon   string
+	camera string
+	fields []string
+}
+
+// add separa ícone e câmera dos demais campos, achatando grupos
+func (l *consoleLine) add(prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	switch {
+	case a.Equal(slog.Attr{}):
+		return
+	case prefix == "" && a.Key == keyIcon:
+		l.icon = a.Value.String()
+		return
+	case prefix == "" && a.Key == KeyCamera:
+		l.camera = a.Value.String()
+		return
+	case a.Value.Kind() == slog.KindGroup:
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			l.add(prefix, ga)
+		}
+		return
+	}
+	l.fields = append(l.fields, prefix+a.Key+"="+formatValue(a.Value))
+}
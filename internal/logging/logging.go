@@ -0,0 +1,106 @@
+// Package logging configura o logger estruturado (log/slog) da aplicação.
+// O formato console mantém a saída legível com ícones; text e json servem
+// a coletores de log.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// Formatos de saída aceitos
+const (
+	FormatConsole = "console"
+	FormatText    = "text"
+	FormatJSON    = "json"
+)
+
+// Campos comuns dos logs
+const (
+	KeyCamera     = "camera"
+	KeyFrame      = "frame"
+	KeyTrackID    = "track_id"
+	KeyBehavior   = "behavior_type"
+	KeyConfidence = "confidence"
+	KeyError      = "error"
+
+	// keyIcon só é exibido pelo formato console
+	keyIcon = "icon"
+)
+
+// New cria o logger no formato pedido, descartando registros abaixo de level
+// ("debug", "info", "warn" ou "error")
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("nível de log inválido: %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: dropIcon}
+	switch format {
+	case FormatConsole:
+		return slog.New(NewConsoleHandler(w, lvl)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("formato de log inválido: %q", format)
+	}
+}
+
+// dropIcon remove o ícone dos formatos estruturados
+func dropIcon(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == keyIcon {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// Icon define o ícone exibido no início da linha no formato console
+func Icon(icon string) slog.Attr {
+	return slog.String(keyIcon, icon)
+}
+
+// Err anexa um erro ao registro
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+// Camera identifica a câmera do registro
+func Camera(id string) slog.Attr {
+	return slog.String(KeyCamera, id)
+}
+
+// Frame identifica o número do frame
+func Frame(number int) slog.Attr {
+	return slog.Int(KeyFrame, number)
+}
+
+// TrackID identifica a trilha (pessoa) do registro
+func TrackID(id int) slog.Attr {
+	return slog.Int(KeyTrackID, id)
+}
+
+// Behavior identifica o tipo de comportamento suspeito
+func Behavior(behaviorType string) slog.Attr {
+	return slog.String(KeyBehavior, behaviorType)
+}
+
+// Confidence registra uma confiança (0..1) com três casas decimais, sem o
+// ruído da conversão de float32
+func Confidence(c float32) slog.Attr {
+	return slog.Float64(KeyConfidence, math.Round(float64(c)*1000)/1000)
+}
+
+// formatValue escreve um valor no formato console, com aspas quando necessário
+func formatValue(v slog.Value) string {
+	s := v.Resolve().String()
+	if s == "" || strings.ContainsAny(s, " =\"\n") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
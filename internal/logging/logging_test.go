@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatConsole, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("invisível")
+	logger.Info("Câmera pronta", Icon("📷"), Camera("cam0"))
+	logger.With(Camera("cam1")).Info("Alerta", Icon("🚨"), Behavior("PERMANENCIA_EXCESSIVA"),
+		Confidence(0.62), TrackID(3), slog.String("description", "Na zona por 25s"))
+	logger.Warn("Erro ao enviar alerta", Err(errors.New("timeout")))
+	logger.WithGroup("fila").Info("Descartes", slog.Int("total", 2))
+
+	want := strings.Join([]string{
+		"📷 [cam0] Câmera pronta",
+		`🚨 [cam1] Alerta behavior_type=PERMANENCIA_EXCESSIVA confidence=0.62 track_id=3 description="Na zona por 25s"`,
+		"⚠️  Erro ao enviar alerta error=timeout",
+		"Descartes fila.total=2",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("saída do console:\n%s\nesperado:\n%s", got, want)
+	}
+}
+
+func TestJSONHandlerDropsIcon(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("Alerta", Icon("🚨"), Camera("cam0"), Frame(42), Confidence(0.7))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	if _, ok := record[keyIcon]; ok {
+		t.Error("ícone não deveria aparecer no JSON")
+	}
+	if record[KeyCamera] != "cam0" || record[KeyFrame] != 42.0 || record[KeyConfidence] != 0.7 {
+		t.Errorf("campos inesperados: %v", record)
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", "info"); err == nil {
+		t.Error("formato inválido deveria falhar")
+	}
+	if _, err := New(&bytes.Buffer{}, FormatText, "verbose"); err == nil {
+		t.Error("nível inválido deveria falhar")
+	}
+}
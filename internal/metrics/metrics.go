@@ -0,0 +1,230 @@
+// Package metrics expõe métricas Prometheus do loop de detecção (FPS,
+// latência de inferência, detecções, trilhas, alertas e falhas de captura).
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"poc-camera/internal/logging"
+)
+
+// Prefixo comum dos nomes das métricas
+const namespace = "poc_camera"
+
+// Filas do pipeline em que frames podem ser descartados (label stage)
+const (
+	DroppedBeforeInference = "inference"
+	DroppedBeforeAnalysis  = "analysis"
+)
+
+// Intervalo mínimo entre atualizações do FPS
+const fpsWindow = time.Second
+
+// Metrics agrupa as métricas do processo em um registry próprio. Os
+// métodos são seguros para uso concorrente.
+type Metrics struct {
+	registry      *prometheus.Registry
+	frames        *prometheus.CounterVec
+	fps           *prometheus.GaugeVec
+	inference     prometheus.Histogram
+	detections    *prometheus.CounterVec
+	activeTracks  *prometheus.GaugeVec
+	trackedPeople *prometheus.GaugeVec
+	alerts        *prometheus.CounterVec
+	dropped       *prometheus.CounterVec
+	readFailures  *prometheus.CounterVec
+	classes       []string // rótulos das classes, indexados pelo ID
+}
+
+// New cria e registra as métricas (mais as de runtime do Go e do processo)
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		frames: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "frames_total",
+			Help:      "Frames analisados por câmera.",
+		}, []string{"camera"}),
+		fps: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fps",
+			Help:      "Frames analisados por segundo, medido a cada segundo.",
+		}, []string{"camera"}),
+		inference: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "inference_duration_seconds",
+			Help:      "Duração de cada inferência do YOLO (um lote de frames).",
+			Buckets:   []float64{.005, .01, .02, .03, .05, .075, .1, .15, .2, .3, .5, 1, 2},
+		}),
+		detections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "detections_total",
+			Help:      "Objetos detectados por câmera e classe.",
+		}, []string{"camera", "class"}),
+		activeTracks: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_tracks",
+			Help:      "Trilhas vivas no tracker (inclui tentativas e perdidas).",
+		}, []string{"camera"}),
+		trackedPeople: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tracked_people",
+			Help:      "Pessoas confirmadas em análise de comportamento.",
+		}, []string{"camera"}),
+		alerts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alerts_total",
+			Help:      "Alertas enviados por câmera e tipo de comportamento.",
+		}, []string{"camera", "type"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped_frames_total",
+			Help:      "Frames descartados por fila cheia, pelo estágio que deixou de recebê-los.",
+		}, []string{"camera", "stage"}),
+		readFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "camera_read_failures_total",
+			Help:      "Leituras da fonte que falharam ou retornaram frame vazio.",
+		}, []string{"camera"}),
+	}
+
+	m.registry.MustRegister(
+		m.frames, m.fps, m.inference, m.detections, m.activeTracks,
+		m.trackedPeople, m.alerts, m.dropped, m.readFailures,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// ObserveInference registra a duração de uma inferência
+func (m *Metrics) ObserveInference(d time.Duration) {
+	m.inference.Observe(d.Seconds())
+}
+
+// Handler retorna o handler HTTP no formato de exposição do Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Serve expõe /metrics em addr. A porta é aberta antes de retornar, para que
+// erros de endereço apareçam na inicialização; o servidor roda em background
+// até Close ou Shutdown.
+func (m *Metrics) Serve(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir servidor de métricas: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Servidor de métricas encerrado", logging.Err(err))
+		}
+	}()
+	return server, nil
+}
+
+// SetClasses define os rótulos das classes (indexados pelo ID, após o
+// class_remap) usados no label class de detections_total. Deve ser chamado
+// antes de Camera.
+func (m *Metrics) SetClasses(names []string) {
+	m.classes = names
+}
+
+// Camera retorna as métricas de uma câmera
+func (m *Metrics) Camera(id string) *Camera {
+	labels := prometheus.Labels{"camera": id}
+	return &Camera{
+		classes:         m.classes,
+		frames:          m.frames.With(labels),
+		fps:             m.fps.With(labels),
+		detections:      m.detections.MustCurryWith(labels),
+		activeTracks:    m.activeTracks.With(labels),
+		trackedPeople:   m.trackedPeople.With(labels),
+		alerts:          m.alerts.MustCurryWith(labels),
+		droppedCapture:  m.dropped.WithLabelValues(id, DroppedBeforeInference),
+		droppedAnalysis: m.dropped.WithLabelValues(id, DroppedBeforeAnalysis),
+		readFailures:    m.readFailures.With(labels),
+	}
+}
+
+// Camera são as métricas de uma câmera. ReadFailure pode ser chamado de
+// qualquer goroutine; os demais métodos, só pela thread de análise.
+type Camera struct {
+	classes         []string
+	frames          prometheus.Counter
+	fps             prometheus.Gauge
+	detections      *prometheus.CounterVec
+	activeTracks    prometheus.Gauge
+	trackedPeople   prometheus.Gauge
+	alerts          *prometheus.CounterVec
+	droppedCapture  prometheus.Counter
+	droppedAnalysis prometheus.Counter
+	readFailures    prometheus.Counter
+
+	windowStart  time.Time
+	windowFrames int
+	lastDropped  [2]int64 // totais já contabilizados (inferência, análise)
+}
+
+// Frame conta um frame analisado e atualiza o FPS a cada segundo
+func (c *Camera) Frame(now time.Time) {
+	c.frames.Inc()
+	if c.windowStart.IsZero() {
+		c.windowStart = now
+		return
+	}
+	c.windowFrames++
+	if elapsed := now.Sub(c.windowStart); elapsed >= fpsWindow {
+		c.fps.Set(float64(c.windowFrames) / elapsed.Seconds())
+		c.windowStart = now
+		c.windowFrames = 0
+	}
+}
+
+// Detection conta um objeto detectado da classe informada. O label é o
+// rótulo da classe (classes remapeadas somam na mesma série); IDs sem
+// rótulo usam o próprio número.
+func (c *Camera) Detection(classID int) {
+	class := strconv.Itoa(classID)
+	if classID >= 0 && classID < len(c.classes) {
+		class = c.classes[classID]
+	}
+	c.detections.WithLabelValues(class).Inc()
+}
+
+// Tracks atualiza as trilhas vivas e as pessoas confirmadas
+func (c *Camera) Tracks(active, people int) {
+	c.activeTracks.Set(float64(active))
+	c.trackedPeople.Set(float64(people))
+}
+
+// Alert conta um alerta enviado
+func (c *Camera) Alert(behaviorType string) {
+	c.alerts.WithLabelValues(behaviorType).Inc()
+}
+
+// Dropped recebe os totais de frames descartados em cada fila do pipeline e
+// soma aos contadores o que mudou desde a última chamada
+func (c *Camera) Dropped(beforeInference, beforeAnalysis int64) {
+	c.droppedCapture.Add(float64(beforeInference - c.lastDropped[0]))
+	c.droppedAnalysis.Add(float64(beforeAnalysis - c.lastDropped[1]))
+	c.lastDropped = [2]int64{beforeInference, beforeAnalysis}
+}
+
+// ReadFailure conta uma leitura da fonte que falhou
+func (c *Camera) ReadFailure() {
+	c.readFailures.Inc()
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCameraMetrics(t *testing.T) {
+	m := New()
+	m.SetClasses([]string{"pessoa", "bolsa"})
+	cam := m.Camera("loja1")
+
+	start := time.Unix(0, 0)
+	for i := 0; i <= 20; i++ {
+		cam.Frame(start.Add(time.Duration(i) * 50 * time.Millisecond))
+	}
+	cam.Detection(0)
+	cam.Detection(0)
+	cam.Detection(1)
+	cam.Tracks(4, 2)
+	cam.Alert("PERMANENCIA_EXCESSIVA")
+	cam.Dropped(3, 1)
+	cam.Dropped(5, 1)
+	cam.ReadFailure()
+	m.ObserveInference(40 * time.Millisecond)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"frames", testutil.ToFloat64(m.frames.WithLabelValues("loja1")), 21},
+		{"fps", testutil.ToFloat64(m.fps.WithLabelValues("loja1")), 20},
+		{"detecções de pessoa", testutil.ToFloat64(m.detections.WithLabelValues("loja1", "pessoa")), 2},
+		{"trilhas ativas", testutil.ToFloat64(m.activeTracks.WithLabelValues("loja1")), 4},
+		{"alertas", testutil.ToFloat64(m.alerts.WithLabelValues("loja1", "PERMANENCIA_EXCESSIVA")), 1},
+		{"descartes antes da inferência", testutil.ToFloat64(m.dropped.WithLabelValues("loja1", DroppedBeforeInference)), 5},
+		{"descartes antes da análise", testutil.ToFloat64(m.dropped.WithLabelValues("loja1", DroppedBeforeAnalysis)), 1},
+		{"falhas de leitura", testutil.ToFloat64(m.readFailures.WithLabelValues("loja1")), 1},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %g, esperado %g", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// Exposição no formato texto
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`poc_camera_inference_duration_seconds_count 1`,
+		`poc_camera_detections_total{camera="loja1",class="bolsa"} 1`,
+		`poc_camera_tracked_people{camera="loja1"} 2`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("saída de /metrics sem %q", want)
+		}
+	}
+}
+
+func TestDetectionLabelsByClass(t *testing.T) {
+	// Rótulos após class_remap: duas classes do modelo viram "celular"
+	m := New()
+	m.SetClasses([]string{"pessoa", "celular", "celular", "bolsa"})
+	cam := m.Camera("loja1")
+
+	// O label é o rótulo da classe, nunca o texto do overlay ("pessoa: 0.87")
+	for _, classID := range []int{0, 0, 1, 2, 3, 7} {
+		cam.Detection(classID)
+	}
+
+	if got := testutil.CollectAndCount(m.detections); got != 4 {
+		t.Errorf("séries de detections_total = %d, esperado 4 (pessoa, celular, bolsa, 7)", got)
+	}
+	tests := []struct {
+		class string
+		want  float64
+	}{{"pessoa", 2}, {"celular", 2}, {"bolsa", 1}, {"7", 1}}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.detections.WithLabelValues("loja1", tt.class)); got != tt.want {
+			t.Errorf("detecções de %s = %g, esperado %g", tt.class, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+)
+
+// Estágios medidos pelo pipeline
+const (
+	StageCapture   = "captura"
+	StageInference = "inferência"
+	StageAnalysis  = "análise"
+	StageTotal     = "total" // da captura até o frame exibido
+)
+
+// StageLatency resume a latência de um estágio
+type StageLatency struct {
+	Stage string
+	Count int
+	Last  time.Duration
+	Mean  time.Duration
+	Max   time.Duration
+}
+
+// stageStats acumula as medições de um estágio
+type stageStats struct {
+	StageLatency
+	sum time.Duration
+}
+
+// Latency acumula a latência de cada estágio; segura para uso concorrente
+type Latency struct {
+	mu     sync.Mutex
+	stages map[string]*stageStats
+	order  []string
+}
+
+// NewLatency cria o acumulador
+func NewLatency() *Latency {
+	return &Latency{stages: make(map[string]*stageStats)}
+}
+
+// Observe registra a duração de uma execução do estágio
+func (l *Latency) Observe(stage string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.stages[stage]
+	if !ok {
+		s = &stageStats{StageLatency: StageLatency{Stage: stage}}
+		l.stages[stage] = s
+		l.order = append(l.order, stage)
+	}
+	s.Count++
+	s.Last = d
+	s.Max = max(s.Max, d)
+	s.sum += d
+	s.Mean = s.sum / time.Duration(s.Count)
+}
+
+// Snapshot retorna os estágios na ordem da primeira medição
+func (l *Latency) Snapshot() []StageLatency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	snapshot := make([]StageLatency, len(l.order))
+	for i, stage := range l.order {
+		snapshot[i] = l.stages[stage].StageLatency
+	}
+	return snapshot
+}
@@ -0,0 +1,105 @@
+// Package pipeline conecta os estágios de captura, inferência e análise por
+// filas limitadas, para que uma inferência lenta não atrase a câmera.
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Queue é uma fila limitada entre dois estágios, com um produtor e um
+// consumidor. Cheia, descarta o item mais antigo (fontes ao vivo) ou
+// bloqueia o produtor (arquivos, onde nenhum frame deve ser perdido).
+type Queue[T any] struct {
+	items      chan T
+	dropOldest bool
+	release    func(T) // libera itens descartados (ex: fecha a gocv.Mat)
+	dropped    atomic.Int64
+}
+
+// NewQueue cria uma fila com capacidade size. release pode ser nil.
+func NewQueue[T any](size int, dropOldest bool, release func(T)) *Queue[T] {
+	if size < 1 {
+		size = 1
+	}
+	if release == nil {
+		release = func(T) {}
+	}
+	return &Queue[T]{
+		items:      make(chan T, size),
+		dropOldest: dropOldest,
+		release:    release,
+	}
+}
+
+// Push enfileira o item. Retorna false se o contexto foi cancelado antes;
+// nesse caso o item é liberado.
+func (q *Queue[T]) Push(ctx context.Context, item T) bool {
+	if !q.dropOldest {
+		select {
+		case q.items <- item:
+			return true
+		case <-ctx.Done():
+			q.release(item)
+			return false
+		}
+	}
+
+	for ctx.Err() == nil {
+		select {
+		case q.items <- item:
+			return true
+		default:
+		}
+
+		// Fila cheia: descarta o mais antigo e tenta de novo
+		select {
+		case old := <-q.items:
+			q.release(old)
+			q.dropped.Add(1)
+		default:
+		}
+	}
+	q.release(item)
+	return false
+}
+
+// Pop retira o próximo item. Retorna false quando a fila foi fechada e
+// esvaziada ou o contexto foi cancelado.
+func (q *Queue[T]) Pop(ctx context.Context) (T, bool) {
+	select {
+	case item, ok := <-q.items:
+		return item, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// TryPop retira o próximo item sem bloquear. ok indica se havia item;
+// closed indica que a fila foi fechada e esvaziada.
+func (q *Queue[T]) TryPop() (item T, ok, closed bool) {
+	select {
+	case next, received := <-q.items:
+		return next, received, !received
+	default:
+		return item, false, false
+	}
+}
+
+// Close sinaliza ao consumidor que não há mais itens (chamado pelo produtor)
+func (q *Queue[T]) Close() {
+	close(q.items)
+}
+
+// Drain libera os itens restantes depois do Close
+func (q *Queue[T]) Drain() {
+	for item := range q.items {
+		q.release(item)
+	}
+}
+
+// Dropped retorna quantos itens foram descartados por fila cheia
+func (q *Queue[T]) Dropped() int64 {
+	return q.dropped.Load()
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueueDropOldest(t *testing.T) {
+	var released []int
+	q := NewQueue(2, true, func(v int) { released = append(released, v) })
+
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		if !q.Push(ctx, i) {
+			t.Fatalf("Push(%d) falhou", i)
+		}
+	}
+	q.Close()
+
+	var got []int
+	for {
+		v, ok := q.Pop(ctx)
+		if !ok {
+			break
+		}
+		got = append(got, v)
+	}
+
+	if want := []int{4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("itens = %v, esperado %v", got, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(released, want) {
+		t.Errorf("liberados = %v, esperado %v", released, want)
+	}
+	if q.Dropped() != 3 {
+		t.Errorf("descartados = %d, esperado 3", q.Dropped())
+	}
+}
+
+func TestQueueBlockingCancel(t *testing.T) {
+	var released []int
+	q := NewQueue(1, false, func(v int) { released = append(released, v) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if !q.Push(ctx, 1) {
+		t.Fatal("Push na fila vazia falhou")
+	}
+	// Fila cheia e sem consumidor: bloqueia até o cancelamento
+	if q.Push(ctx, 2) {
+		t.Fatal("Push deveria falhar após o cancelamento")
+	}
+	if q.Dropped() != 0 {
+		t.Errorf("fila bloqueante descartou %d itens", q.Dropped())
+	}
+
+	q.Close()
+	q.Drain()
+	if want := []int{2, 1}; !reflect.DeepEqual(released, want) {
+		t.Errorf("liberados = %v, esperado %v", released, want)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	l := NewLatency()
+	l.Observe(StageInference, 30*time.Millisecond)
+	l.Observe(StageCapture, 5*time.Millisecond)
+	l.Observe(StageInference, 10*time.Millisecond)
+
+	got := l.Snapshot()
+	want := []StageLatency{
+		{Stage: StageInference, Count: 2, Last: 10 * time.Millisecond, Mean: 20 * time.Millisecond, Max: 30 * time.Millisecond},
+		{Stage: StageCapture, Count: 1, Last: 5 * time.Millisecond, Mean: 5 * time.Millisecond, Max: 5 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot = %+v, esperado %+v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package pipeline
+
+import "time"
+
+// Suavização da média de latência do modo adaptativo
+const latencySmoothing = 0.2
+
+// Scheduler decide em quais frames a detecção roda. Com passo N, detecta um
+// frame a cada N; no modo adaptativo (target > 0) o passo cresce até
+// maxStride enquanto a latência média da inferência passa do alvo e volta a
+// diminuir quando fica bem abaixo dele. Usado por uma única goroutine.
+type Scheduler struct {
+	baseStride int
+	maxStride  int
+	target     time.Duration
+
+	stride    int
+	countdown int
+	average   time.Duration
+}
+
+// NewScheduler cria o agendador com passo fixo stride ou, com target > 0,
+// adaptativo entre stride e maxStride
+func NewScheduler(stride, maxStride int, target time.Duration) *Scheduler {
+	stride = max(stride, 1)
+	return &Scheduler{
+		baseStride: stride,
+		maxStride:  max(maxStride, stride),
+		target:     target,
+		stride:     stride,
+	}
+}
+
+// Due informa se o frame atual deve passar pela detecção
+func (s *Scheduler) Due() bool {
+	if s.countdown > 0 {
+		s.countdown--
+		return false
+	}
+	s.countdown = s.stride - 1
+	return true
+}
+
+// Observe registra a latência de uma detecção e, no modo adaptativo,
+// ajusta o passo
+func (s *Scheduler) Observe(latency time.Duration) {
+	if s.target <= 0 {
+		return
+	}
+	if s.average == 0 {
+		s.average = latency
+	} else {
+		s.average += time.Duration(latencySmoothing * float64(latency-s.average))
+	}
+
+	switch {
+	case s.average > s.target && s.stride < s.maxStride:
+		s.stride++
+	case s.average < s.target*7/10 && s.stride > s.baseStride:
+		s.stride--
+	}
+}
+
+// Stride retorna o passo atual
+func (s *Scheduler) Stride() int {
+	return s.stride
+}
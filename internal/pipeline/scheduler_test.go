@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+)
+
+// pattern roda n frames e marca com 'D' os que passam pela detecção
+func pattern(s *Scheduler, n int, latency time.Duration) string {
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = '.'
+		if s.Due() {
+			out[i] = 'D'
+			s.Observe(latency)
+		}
+	}
+	return string(out)
+}
+
+func TestSchedulerFixedStride(t *testing.T) {
+	tests := []struct {
+		stride int
+		want   string
+	}{
+		{stride: 1, want: "DDDDDD"},
+		{stride: 3, want: "D..D.."},
+		{stride: 0, want: "DDDDDD"}, // tratado como 1
+	}
+	for _, tt := range tests {
+		// Sem alvo, a latência não altera o passo
+		if got := pattern(NewScheduler(tt.stride, 10, 0), 6, time.Second); got != tt.want {
+			t.Errorf("passo %d: %s, esperado %s", tt.stride, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulerAdaptive(t *testing.T) {
+	s := NewScheduler(1, 3, 50*time.Millisecond)
+
+	// Inferência lenta: o passo sobe até o máximo
+	if got, want := pattern(s, 10, 100*time.Millisecond), "DD.D..D..D"; got != want {
+		t.Errorf("com latência alta: %s, esperado %s", got, want)
+	}
+	if s.Stride() != 3 {
+		t.Errorf("passo = %d, esperado 3", s.Stride())
+	}
+
+	// Inferência rápida: a média cai abaixo de 70% do alvo e o passo volta ao base
+	pattern(s, 60, 10*time.Millisecond)
+	if s.Stride() != 1 {
+		t.Errorf("passo = %d, esperado 1", s.Stride())
+	}
+}
@@ -0,0 +1,73 @@
+package shoplifting
+
+import (
+	"image"
+
+	"gocv.io/x/gocv"
+)
+
+// Classes usadas nos testes (pessoa precisa ser a classe 0)
+var testClassNames = []string{"pessoa", "celular"}
+
+const (
+	testPersonClass = 0
+	testPhoneClass  = 1
+)
+
+// fakeDetector devolve uma sequência roteirizada de detecções, um item por
+// chamada de Detect. Depois do fim do roteiro não detecta mais nada.
+type fakeDetector struct {
+	frames [][]DetectionResult
+	next   int
+}
+
+// Detect implementa ObjectDetector ignorando a imagem
+func (fd *fakeDetector) Detect(img gocv.Mat) []DetectionResult {
+	if fd.next >= len(fd.frames) {
+		return nil
+	}
+	detections := fd.frames[fd.next]
+	fd.next++
+	return detections
+}
+
+// person cria uma detecção de pessoa com o canto superior esquerdo em (x, y)
+func person(x, y int) DetectionResult {
+	return DetectionResult{
+		ClassID:    testPersonClass,
+		Confidence: 0.9,
+		Box:        image.Rect(x, y, x+60, y+150),
+		Label:      "pessoa",
+	}
+}
+
+// phone cria uma detecção de celular com o canto superior esquerdo em (x, y)
+func phone(x, y int) DetectionResult {
+	return DetectionResult{
+		ClassID:    testPhoneClass,
+		Confidence: 0.8,
+		Box:        image.Rect(x, y, x+30, y+30),
+		Label:      "celular",
+	}
+}
+
+// script gera n frames a partir de uma função do índice do frame
+func script(n int, frame func(i int) []DetectionResult) [][]DetectionResult {
+	frames := make([][]DetectionResult, n)
+	for i := range frames {
+		frames[i] = frame(i)
+	}
+	return frames
+}
+
+// stationary mantém uma pessoa parada por n frames
+func stationary(n int) [][]DetectionResult {
+	return script(n, func(int) []DetectionResult {
+		return []DetectionResult{person(100, 100)}
+	})
+}
+
+// empty gera n frames sem detecções
+func empty(n int) [][]DetectionResult {
+	return script(n, func(int) []DetectionResult { return nil })
+}
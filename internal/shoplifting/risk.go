@@ -0,0 +1,172 @@
+package shoplifting
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Fatores que compõem a pontuação de risco
+const (
+	riskLoitering = "permanencia"
+	riskProximity = "proximidade"
+	riskMovement  = "movimento"
+	riskZone      = "zona"
+)
+
+// riskFactorOrder define a ordem dos fatores nos detalhes do alerta
+var riskFactorOrder = []string{riskLoitering, riskProximity, riskMovement, riskZone}
+
+// riskFactors associa cada tipo de comportamento ao seu fator de risco
+var riskFactors = map[string]string{
+	"PERMANENCIA_EXCESSIVA": riskLoitering,
+	"PROXIMIDADE_SUSPEITA":  riskProximity,
+	"MOVIMENTO_SUSPEITO":    riskMovement,
+	"ACESSO_RESTRITO":       riskZone,
+	"SAIDA_SEM_CAIXA":       riskZone,
+}
+
+// riskEvents são os comportamentos pontuais (emitidos uma vez, não a cada
+// frame): somam peso × confiança de uma vez, em vez de por segundo
+var riskEvents = map[string]bool{
+	"MOVIMENTO_SUSPEITO": true,
+	"SAIDA_SEM_CAIXA":    true,
+}
+
+// riskWeight retorna o peso configurado de um fator de risco
+func (sd *ShopliftingDetector) riskWeight(factor string) float64 {
+	switch factor {
+	case riskLoitering:
+		return sd.config.RiskWeightLoitering
+	case riskProximity:
+		return sd.config.RiskWeightProximity
+	case riskMovement:
+		return sd.config.RiskWeightMovement
+	case riskZone:
+		return sd.config.RiskWeightZone
+	}
+	return 0
+}
+
+// updateRisk funde os comportamentos da pessoa no frame em uma pontuação de
+// risco acumulada: cada fator decai pela meia-vida configurada e recebe
+// peso × confiança (por segundo nos comportamentos contínuos, de uma vez nos
+// pontuais). A pontuação é a soma dos fatores, limitada a 1. Retorna o
+// comportamento RISCO_ELEVADO enquanto a pontuação está acima do limite.
+func (sd *ShopliftingDetector) updateRisk(tracked *TrackedPerson, behaviors []SuspiciousBehavior, currentTime time.Time) []SuspiciousBehavior {
+	// Decaimento desde a última atualização
+	var elapsed float64
+	if !tracked.RiskUpdated.IsZero() {
+		elapsed = currentTime.Sub(tracked.RiskUpdated).Seconds()
+	}
+	tracked.RiskUpdated = currentTime
+	if elapsed > 0 && sd.config.RiskHalfLife > 0 {
+		decay := math.Exp2(-elapsed / sd.config.RiskHalfLife)
+		for factor := range tracked.RiskFactors {
+			tracked.RiskFactors[factor] *= decay
+		}
+	}
+
+	// Maior confiança de cada fator no frame (vários itens valiosos próximos
+	// não multiplicam a proximidade)
+	continuous := make(map[string]float64)
+	for _, b := range behaviors {
+		factor, ok := riskFactors[b.Type]
+		if !ok {
+			continue
+		}
+		weight := sd.riskWeight(factor)
+		if riskEvents[b.Type] {
+			tracked.RiskFactors[factor] += weight * float64(b.Confidence)
+			continue
+		}
+		continuous[factor] = math.Max(continuous[factor], weight*float64(b.Confidence))
+	}
+	for factor, rate := range continuous {
+		tracked.RiskFactors[factor] += rate * elapsed
+	}
+
+	// A soma é limitada a 1 reduzindo os fatores na mesma proporção, para que
+	// o decaimento apareça logo que os comportamentos cessam
+	var score float64
+	for _, value := range tracked.RiskFactors {
+		score += value
+	}
+	if score > 1 {
+		for factor := range tracked.RiskFactors {
+			tracked.RiskFactors[factor] /= score
+		}
+		score = 1
+	}
+	tracked.RiskScore = score
+
+	threshold := sd.config.RiskThreshold
+	if threshold <= 0 || tracked.RiskScore < threshold {
+		tracked.RiskAlerted = false
+		return nil
+	}
+
+	// Cada cruzamento do limite conta como um episódio suspeito da pessoa
+	if !tracked.RiskAlerted {
+		tracked.RiskAlerted = true
+		tracked.SuspiciousCount++
+	}
+
+	parts := make([]string, 0, len(riskFactorOrder))
+	for _, factor := range riskFactorOrder {
+		if value := tracked.RiskFactors[factor]; value >= 0.01 {
+			parts = append(parts, fmt.Sprintf("%s: %.2f", factor, value))
+		}
+	}
+	return []SuspiciousBehavior{{
+		Type:        "RISCO_ELEVADO",
+		Confidence:  float32(tracked.RiskScore),
+		Description: fmt.Sprintf("Pontuação de risco %.2f (limite %.2f)", tracked.RiskScore, threshold),
+		Details:     fmt.Sprintf("%s | Episódios: %d", strings.Join(parts, " | "), tracked.SuspiciousCount),
+		PersonID:    tracked.ID,
+		Location:    tracked.Positions[len(tracked.Positions)-1],
+		ShouldLog:   sd.shouldLogBehavior(tracked, "RISCO_ELEVADO"),
+	}}
+}
+
+// riskColor vai de verde (sem risco) a vermelho (risco máximo)
+func riskColor(score float64) color.RGBA {
+	return color.RGBA{uint8(255 * score), uint8(255 * (1 - score)), 0, 255}
+}
+
+// seenInLastFrame retorna as pessoas atualizadas no último frame processado.
+// A comparação usa o instante registrado em applyTracks: com o relógio do
+// sistema, cada chamada a Now retorna um instante diferente.
+func (sd *ShopliftingDetector) seenInLastFrame() []*TrackedPerson {
+	var seen []*TrackedPerson
+	for _, tracked := range sd.trackedPeople {
+		if len(tracked.Positions) > 0 && tracked.LastSeen.Equal(sd.frameTime) {
+			seen = append(seen, tracked)
+		}
+	}
+	return seen
+}
+
+// DrawRiskScores desenha a pontuação de risco das pessoas vistas no último
+// frame, em barra e valor abaixo do centro de cada uma
+func (sd *ShopliftingDetector) DrawRiskScores(img *gocv.Mat) {
+	for _, tracked := range sd.seenInLastFrame() {
+		center := tracked.Positions[len(tracked.Positions)-1]
+		riskCol := riskColor(tracked.RiskScore)
+
+		bar := image.Rect(center.X-40, center.Y+10, center.X+40, center.Y+18)
+		gocv.Rectangle(img, bar, color.RGBA{255, 255, 255, 255}, 1)
+		filled := bar
+		filled.Max.X = bar.Min.X + int(float64(bar.Dx())*tracked.RiskScore)
+		gocv.Rectangle(img, filled, riskCol, -1)
+
+		gocv.PutText(img, fmt.Sprintf("Risco #%d: %.2f", tracked.ID, tracked.RiskScore),
+			image.Pt(bar.Min.X, bar.Max.Y+15),
+			gocv.FontHersheySimplex, 0.45, riskCol, 1)
+	}
+}
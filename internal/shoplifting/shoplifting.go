@@ -4,12 +4,22 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"log/slog"
 	"math"
 	"strings"
 	"time"
 
 	"gocv.io/x/gocv"
 	"poc-camera/config"
+	"poc-camera/internal/clock"
+	"poc-camera/internal/logging"
+	"poc-camera/internal/tracking"
+)
+
+// Limites de memória por pessoa rastreada
+const (
+	maxPositions        = 30          // aproximadamente 1 segundo a 30fps
+	logThrottleInterval = time.Second // intervalo mínimo entre logs do mesmo tipo
 )
 
 // TrackedPerson representa uma pessoa sendo rastreada ao longo do tempo
@@ -22,6 +32,13 @@ type TrackedPerson struct {
 	FirstSeen       time.Time
 	LastSuspiciousMovement time.Time // Para cooldown
 	LastLogTimes    map[string]time.Time // Para throttling de logs por tipo
+	Zones            map[string]ZonePresence // Zonas em que a pessoa está agora
+	VisitedZoneTypes map[string]bool         // Tipos de zona já visitados
+	ExitAlerted      bool                    // Alerta de saída sem caixa já emitido
+	RiskScore        float64                 // Pontuação de risco fundida (0..1)
+	RiskFactors      map[string]float64      // Contribuição de cada fator à pontuação
+	RiskUpdated      time.Time               // Última atualização da pontuação
+	RiskAlerted      bool                    // Pontuação acima do limite desde o último cruzamento
 }
 
 // SuspiciousBehavior representa um comportamento suspeito detectado
@@ -52,30 +69,95 @@ type ObjectDetector interface {
 type ShopliftingDetector struct {
 	objectDetector ObjectDetector
 	trackedPeople  map[int]*TrackedPerson
-	nextPersonID   int
+	tracker        tracking.Tracker
+	clock          clock.Clock
 	config         *config.Config
-	valuableItems  map[int]string
+	valuableItems  map[int]config.ValuableItem
+	zones          []zone
+	confirmed      []tracking.Track
+	lastValuable   []DetectionResult // itens valiosos da última detecção
+	frameTime      time.Time         // instante do último frame processado
+	personLabel    string
 	frameCount     int
 }
 
-// NewShopliftingDetector cria um novo detector de shoplifting
-func NewShopliftingDetector(objectDetector ObjectDetector, cfg *config.Config) (*ShopliftingDetector, error) {
-	fmt.Println("✅ Sistema funcionando com:")
-	fmt.Println("   • Detecção de objetos (365 classes)")
-	fmt.Println("   • Tracking de pessoas")
-	fmt.Println("   • Detecção de loitering (tempo)")
-	fmt.Println("   • Proximidade com itens valiosos")
-	fmt.Println("   • Análise comportamental baseada em movimento")
+// NewShopliftingDetector cria um novo detector de shoplifting.
+// classNames é a lista de classes do modelo, usada para resolver o catálogo de itens valiosos.
+func NewShopliftingDetector(objectDetector ObjectDetector, cfg *config.Config, classNames []string) (*ShopliftingDetector, error) {
+	valuableItems, err := cfg.ResolveValuableItems(classNames)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := tracking.New(cfg.TrackerType, tracking.Options{
+		MaxAge:             time.Duration(cfg.TrackerTimeout * float64(time.Second)),
+		ProximityThreshold: cfg.ProximityThreshold,
+		IoUThreshold:       cfg.TrackerIoUThreshold,
+		MinHits:            cfg.TrackerMinHits,
+		HighConfidence:     cfg.TrackerHighConfidence,
+		MaxTracks:          cfg.MaxTrackedPeople,
+		EvictionPolicy:     cfg.TrackEvictionPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Análise de shoplifting pronta", logging.Icon("✅"), logging.Camera(cfg.CameraID),
+		slog.Int("classes", len(classNames)), slog.String("tracker", cfg.TrackerType),
+		slog.Int("valuable_items", len(cfg.ValuableItems)), slog.Int("zones", len(cfg.Zones)))
+
+	personLabel := "pessoa"
+	if len(classNames) > 0 {
+		personLabel = classNames[0]
+	}
 
 	return &ShopliftingDetector{
 		objectDetector: objectDetector,
+		personLabel:    personLabel,
 		trackedPeople:  make(map[int]*TrackedPerson),
-		nextPersonID:   1,
+		tracker:        tracker,
+		clock:          clock.System{},
 		config:         cfg,
-		valuableItems:  config.GetValuableItems(),
+		valuableItems:  valuableItems,
+		zones:          newZones(cfg.Zones, cfg.LoiteringTimeThreshold),
 	}, nil
 }
 
+// TrackingStats resume o estado do tracking de pessoas
+type TrackingStats struct {
+	TrackedPeople int // pessoas rastreadas no momento
+	ActiveTracks  int // trilhas vivas no tracker (inclui tentativas e perdidas)
+	Evictions     int // trilhas removidas por atingir MaxTrackedPeople
+}
+
+// TrackingStats retorna contadores do tracking
+func (sd *ShopliftingDetector) TrackingStats() TrackingStats {
+	stats := sd.tracker.Stats()
+	return TrackingStats{
+		TrackedPeople: len(sd.trackedPeople),
+		ActiveTracks:  stats.Active,
+		Evictions:     stats.Evictions,
+	}
+}
+
+// SetClock troca a origem do tempo da análise (padrão: relógio do sistema).
+// Com um relógio guiado pelos frames, o replay de um arquivo em qualquer
+// velocidade produz os mesmos resultados.
+func (sd *ShopliftingDetector) SetClock(c clock.Clock) {
+	sd.clock = c
+}
+
+// Now retorna o instante atual segundo o relógio da análise
+func (sd *ShopliftingDetector) Now() time.Time {
+	return sd.clock.Now()
+}
+
+// ConfirmedTracks retorna as trilhas confirmadas no último frame processado
+// (o slice é reaproveitado no frame seguinte)
+func (sd *ShopliftingDetector) ConfirmedTracks() []tracking.Track {
+	return sd.confirmed
+}
+
 // Close libera recursos do detector
 func (sd *ShopliftingDetector) Close() {
 	// Nenhum recurso adicional para liberar
@@ -84,11 +166,20 @@ func (sd *ShopliftingDetector) Close() {
 // DetectShoplifting executa detecção completa de shoplifting
 func (sd *ShopliftingDetector) DetectShoplifting(img gocv.Mat) ([]DetectionResult, []SuspiciousBehavior) {
 	// 1. Detecta objetos (incluindo pessoas)
-	detections := sd.objectDetector.Detect(img)
+	return sd.Analyze(sd.objectDetector.Detect(img))
+}
+
+// Analyze executa a análise comportamental sobre detecções já calculadas
+// (usado quando a inferência roda em outro estágio do pipeline). Retorna as
+// detecções fora das zonas ignore e os comportamentos suspeitos.
+func (sd *ShopliftingDetector) Analyze(detections []DetectionResult) ([]DetectionResult, []SuspiciousBehavior) {
+	// Descarta detecções em regiões mascaradas (zonas ignore)
+	detections = sd.filterMasked(detections)
 
 	// 2. Filtra pessoas e objetos valiosos
 	people := sd.filterPeople(detections)
 	valuableObjects := sd.filterValuableObjects(detections)
+	sd.lastValuable = valuableObjects
 
 	// 3. Atualiza tracking de pessoas
 	sd.updateTracking(people)
@@ -102,6 +193,29 @@ func (sd *ShopliftingDetector) DetectShoplifting(img gocv.Mat) ([]DetectionResul
 	return detections, suspiciousBehaviors
 }
 
+// Interpolate atualiza a análise em um frame sem detecção (detecção a cada N
+// frames): as pessoas seguem a previsão do tracker e os itens valiosos ficam
+// na posição da última detecção. Retorna as caixas previstas e os
+// comportamentos suspeitos, como Analyze.
+func (sd *ShopliftingDetector) Interpolate() ([]DetectionResult, []SuspiciousBehavior) {
+	sd.applyTracks(sd.tracker.Predict(sd.clock.Now()))
+
+	people := make([]DetectionResult, len(sd.confirmed))
+	for i, track := range sd.confirmed {
+		people[i] = DetectionResult{
+			ClassID:    0,
+			Confidence: track.Confidence,
+			Box:        track.Box,
+			Label:      fmt.Sprintf("%s: %.2f", sd.personLabel, track.Confidence),
+		}
+	}
+
+	suspiciousBehaviors := sd.analyzeBehaviors(people, sd.lastValuable)
+	sd.cleanupOldTracking()
+
+	return append(people, sd.lastValuable...), suspiciousBehaviors
+}
+
 // filterPeople filtra detecções que são pessoas (class ID 0 normalmente)
 func (sd *ShopliftingDetector) filterPeople(detections []DetectionResult) []DetectionResult {
 	var people []DetectionResult
@@ -129,34 +243,47 @@ func (sd *ShopliftingDetector) filterValuableObjects(detections []DetectionResul
 
 // updateTracking atualiza tracking de pessoas
 func (sd *ShopliftingDetector) updateTracking(people []DetectionResult) {
-	currentTime := time.Now()
+	detections := make([]tracking.Detection, len(people))
+	for i, person := range people {
+		detections[i] = tracking.Detection{Box: person.Box, Confidence: person.Confidence}
+	}
 
 	// Associa detecções com pessoas rastreadas
-	for _, person := range people {
-		personCenter := image.Pt(
-			person.Box.Min.X+person.Box.Dx()/2,
-			person.Box.Min.Y+person.Box.Dy()/2,
-		)
+	sd.applyTracks(sd.tracker.Update(detections, sd.clock.Now()))
+}
 
-		// Procura pessoa existente próxima
-		trackedID := sd.findNearestTrackedPerson(personCenter)
+// applyTracks atualiza as pessoas rastreadas a partir das trilhas do tracker
+func (sd *ShopliftingDetector) applyTracks(tracks []tracking.Track) {
+	currentTime := sd.clock.Now()
+	sd.frameTime = currentTime
 
-		if trackedID == -1 {
+	alive := make(map[int]bool, len(tracks))
+	sd.confirmed = sd.confirmed[:0]
+	for _, track := range tracks {
+		alive[track.ID] = true
+
+		// Só trilhas confirmadas e vistas neste frame atualizam a pessoa
+		if track.State != tracking.StateConfirmed {
+			continue
+		}
+		sd.confirmed = append(sd.confirmed, track)
+		personCenter := track.Center()
+
+		tracked, exists := sd.trackedPeople[track.ID]
+		if !exists {
 			// Nova pessoa
-			trackedID = sd.nextPersonID
-			sd.nextPersonID++
-
-			sd.trackedPeople[trackedID] = &TrackedPerson{
-				ID:           trackedID,
-				FirstSeen:    currentTime,
-				LastSeen:     currentTime,
-				Positions:    []image.Point{personCenter},
-				LastLogTimes: make(map[string]time.Time),
+			tracked = &TrackedPerson{
+				ID:               track.ID,
+				FirstSeen:        track.FirstSeen,
+				LastLogTimes:     make(map[string]time.Time),
+				Zones:            make(map[string]ZonePresence),
+				VisitedZoneTypes: make(map[string]bool),
+				RiskFactors:      make(map[string]float64),
 			}
+			sd.trackedPeople[track.ID] = tracked
 		}
 
 		// Atualiza pessoa rastreada
-		tracked := sd.trackedPeople[trackedID]
 		tracked.LastSeen = currentTime
 		tracked.Positions = append(tracked.Positions, personCenter)
 
@@ -164,43 +291,36 @@ func (sd *ShopliftingDetector) updateTracking(people []DetectionResult) {
 		tracked.LoiteringTime = currentTime.Sub(tracked.FirstSeen)
 
 		// Limita histórico de posições
-		maxPositions := 30 // aproximadamente 1 segundo a 30fps
 		if len(tracked.Positions) > maxPositions {
 			tracked.Positions = tracked.Positions[1:]
 		}
-	}
-}
 
-// findNearestTrackedPerson encontra pessoa rastreada mais próxima
-func (sd *ShopliftingDetector) findNearestTrackedPerson(center image.Point) int {
-	minDistance := float64(sd.config.ProximityThreshold)
-	nearestID := -1
+		// Atualiza permanência por zona
+		sd.updateZones(tracked, track.Box, currentTime)
 
-	for id, tracked := range sd.trackedPeople {
-		if len(tracked.Positions) == 0 {
-			continue
+		// Timestamps de log fora da janela de throttling não são mais necessários
+		for key, lastLog := range tracked.LastLogTimes {
+			if currentTime.Sub(lastLog) >= logThrottleInterval {
+				delete(tracked.LastLogTimes, key)
+			}
 		}
+	}
 
-		lastPos := tracked.Positions[len(tracked.Positions)-1]
-		distance := math.Sqrt(float64((center.X-lastPos.X)*(center.X-lastPos.X) +
-			(center.Y-lastPos.Y)*(center.Y-lastPos.Y)))
-
-		if distance < minDistance {
-			minDistance = distance
-			nearestID = id
+	// Pessoas cujas trilhas o tracker descartou (ex: removidas por capacidade)
+	for id := range sd.trackedPeople {
+		if !alive[id] {
+			delete(sd.trackedPeople, id)
 		}
 	}
-
-	return nearestID
 }
 
 // shouldLogBehavior verifica se um comportamento deve ser logado baseado em throttling (1x por segundo)
 func (sd *ShopliftingDetector) shouldLogBehavior(tracked *TrackedPerson, behaviorType string) bool {
-	currentTime := time.Now()
+	currentTime := sd.clock.Now()
 
 	if lastLog, exists := tracked.LastLogTimes[behaviorType]; exists {
 		// Se logou há menos de 1 segundo, não loga novamente
-		if currentTime.Sub(lastLog).Seconds() < 1.0 {
+		if currentTime.Sub(lastLog) < logThrottleInterval {
 			return false
 		}
 	}
@@ -213,10 +333,17 @@ func (sd *ShopliftingDetector) shouldLogBehavior(tracked *TrackedPerson, behavio
 // analyzeBehaviors analisa comportamentos suspeitos
 func (sd *ShopliftingDetector) analyzeBehaviors(people []DetectionResult, valuableObjects []DetectionResult) []SuspiciousBehavior {
 	var behaviors []SuspiciousBehavior
+	currentTime := sd.clock.Now()
+
+	// Com zonas de prateleira configuradas, a permanência é medida por zona
+	perZoneDwell := sd.hasDwellZones()
 
 	for id, tracked := range sd.trackedPeople {
+		// Comportamentos desta pessoa entram na pontuação de risco
+		personStart := len(behaviors)
+
 		// Análise de tempo de permanência (loitering)
-		if tracked.LoiteringTime.Seconds() > sd.config.LoiteringTimeThreshold {
+		if !perZoneDwell && tracked.LoiteringTime.Seconds() > sd.config.LoiteringTimeThreshold {
 			behaviors = append(behaviors, SuspiciousBehavior{
 				Type:        "PERMANENCIA_EXCESSIVA",
 				Confidence:  float32(math.Min(tracked.LoiteringTime.Seconds()/30.0, 1.0)),
@@ -228,6 +355,11 @@ func (sd *ShopliftingDetector) analyzeBehaviors(people []DetectionResult, valuab
 			})
 		}
 
+		// Análise baseada nas zonas da loja
+		if len(tracked.Positions) > 0 {
+			behaviors = append(behaviors, sd.analyzeZones(tracked, tracked.Positions[len(tracked.Positions)-1], currentTime)...)
+		}
+
 		// Análise de proximidade com objetos valiosos
 		if len(tracked.Positions) > 0 {
 			lastPos := tracked.Positions[len(tracked.Positions)-1]
@@ -241,12 +373,15 @@ func (sd *ShopliftingDetector) analyzeBehaviors(people []DetectionResult, valuab
 					(lastPos.Y-valuableCenter.Y)*(lastPos.Y-valuableCenter.Y)))
 
 				if distance < sd.config.ProximityThreshold {
-					behaviorKey := fmt.Sprintf("PROXIMIDADE_SUSPEITA_%s", valuable.Label)
+					item := sd.valuableItems[valuable.ClassID]
+					// Chave por classe (o label inclui a confiança e geraria uma chave por frame)
+					behaviorKey := fmt.Sprintf("PROXIMIDADE_SUSPEITA_%d", valuable.ClassID)
 					behaviors = append(behaviors, SuspiciousBehavior{
 						Type:        "PROXIMIDADE_SUSPEITA",
-						Confidence:  float32(1.0 - distance/sd.config.ProximityThreshold),
+						// Itens de maior valor geram alertas mais confiantes
+						Confidence:  float32(1.0-distance/sd.config.ProximityThreshold) * item.Weight,
 						Description: fmt.Sprintf("Próximo a %s", valuable.Label),
-						Details:     fmt.Sprintf("Distância: %.1f pixels | Limite: %.1f pixels", distance, sd.config.ProximityThreshold),
+						Details:     fmt.Sprintf("Distância: %.1f pixels | Limite: %.1f pixels | Categoria: %s | Peso: %.2f", distance, sd.config.ProximityThreshold, item.Category, item.Weight),
 						PersonID:    id,
 						Location:    lastPos,
 						ShouldLog:   sd.shouldLogBehavior(tracked, behaviorKey),
@@ -257,7 +392,6 @@ func (sd *ShopliftingDetector) analyzeBehaviors(people []DetectionResult, valuab
 
 		// Análise de movimento suspeito (apenas movimento recente com cooldown)
 		if len(tracked.Positions) > 15 {
-			currentTime := time.Now()
 			// Cooldown de 8 segundos entre alertas de movimento suspeito
 			if currentTime.Sub(tracked.LastSuspiciousMovement).Seconds() > 8.0 {
 				// Analisa apenas as últimas 12 posições (movimento bem recente)
@@ -288,6 +422,8 @@ func (sd *ShopliftingDetector) analyzeBehaviors(people []DetectionResult, valuab
 			}
 		}
 
+		// Pontuação de risco fundida a partir dos comportamentos do frame
+		behaviors = append(behaviors, sd.updateRisk(tracked, behaviors[personStart:], currentTime)...)
 	}
 
 	return behaviors
@@ -417,7 +553,7 @@ func (sd *ShopliftingDetector) analyzeSuspiciousMovement(positions []image.Point
 
 // cleanupOldTracking remove pessoas que não são mais vistas
 func (sd *ShopliftingDetector) cleanupOldTracking() {
-	currentTime := time.Now()
+	currentTime := sd.clock.Now()
 
 	for id, tracked := range sd.trackedPeople {
 		if currentTime.Sub(tracked.LastSeen).Seconds() > sd.config.TrackerTimeout {
@@ -0,0 +1,325 @@
+package shoplifting
+
+import (
+	"testing"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/config"
+	"poc-camera/internal/clock"
+)
+
+// testStart é o instante inicial do relógio manual
+var testStart = time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)
+
+// newTestDetector cria um detector alimentado pelo roteiro, com relógio manual
+func newTestDetector(t *testing.T, frames [][]DetectionResult, configure func(*config.Config)) (*ShopliftingDetector, *clock.Manual) {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.ValuableItems = []config.ValuableItem{{Name: "celular", Category: "eletrônicos", Weight: 1.0}}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	sd, err := NewShopliftingDetector(&fakeDetector{frames: frames}, cfg, testClassNames)
+	if err != nil {
+		t.Fatalf("NewShopliftingDetector: %v", err)
+	}
+	clk := clock.NewManual(testStart)
+	sd.SetClock(clk)
+	return sd, clk
+}
+
+// run processa todos os frames do roteiro avançando step entre eles e
+// retorna os comportamentos de cada frame
+func run(sd *ShopliftingDetector, clk *clock.Manual, frames int, step time.Duration) [][]SuspiciousBehavior {
+	results := make([][]SuspiciousBehavior, frames)
+	for i := 0; i < frames; i++ {
+		if i > 0 {
+			clk.Advance(step)
+		}
+		_, results[i] = sd.DetectShoplifting(gocv.Mat{})
+	}
+	return results
+}
+
+// countBehaviors conta comportamentos de um tipo; logged conta só os que seriam logados
+func countBehaviors(results [][]SuspiciousBehavior, behaviorType string, logged bool) int {
+	count := 0
+	for _, frame := range results {
+		for _, b := range frame {
+			if b.Type == behaviorType && (!logged || b.ShouldLog) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestBehaviors(t *testing.T) {
+	tests := []struct {
+		name     string
+		frames   [][]DetectionResult
+		step     time.Duration
+		behavior string
+		want     bool
+	}{
+		{
+			name:     "permanência acima do limite",
+			frames:   stationary(250), // ~25s
+			step:     100 * time.Millisecond,
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     true,
+		},
+		{
+			name:     "permanência abaixo do limite",
+			frames:   stationary(150), // ~15s
+			step:     100 * time.Millisecond,
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     false,
+		},
+		{
+			name: "proximidade com item valioso",
+			frames: script(10, func(int) []DetectionResult {
+				return []DetectionResult{person(100, 100), phone(150, 170)}
+			}),
+			step:     100 * time.Millisecond,
+			behavior: "PROXIMIDADE_SUSPEITA",
+			want:     true,
+		},
+		{
+			name: "item valioso distante",
+			frames: script(10, func(int) []DetectionResult {
+				return []DetectionResult{person(100, 100), phone(600, 500)}
+			}),
+			step:     100 * time.Millisecond,
+			behavior: "PROXIMIDADE_SUSPEITA",
+			want:     false,
+		},
+		{
+			name: "movimento errático em zigue-zague",
+			frames: script(30, func(i int) []DetectionResult {
+				return []DetectionResult{person(100+(i%2)*20, 100)}
+			}),
+			step:     100 * time.Millisecond,
+			behavior: "MOVIMENTO_SUSPEITO",
+			want:     true,
+		},
+		{
+			name: "caminhada em linha reta",
+			frames: script(30, func(i int) []DetectionResult {
+				return []DetectionResult{person(100+i*10, 100)}
+			}),
+			step:     100 * time.Millisecond,
+			behavior: "MOVIMENTO_SUSPEITO",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, clk := newTestDetector(t, tt.frames, nil)
+			results := run(sd, clk, len(tt.frames), tt.step)
+
+			got := countBehaviors(results, tt.behavior, false) > 0
+			if got != tt.want {
+				t.Errorf("%s detectado = %v, esperado %v", tt.behavior, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMovementCooldown(t *testing.T) {
+	// Zigue-zague contínuo por 20s: um alerta a cada 8s de cooldown
+	frames := script(200, func(i int) []DetectionResult {
+		return []DetectionResult{person(100+(i%2)*20, 100)}
+	})
+	sd, clk := newTestDetector(t, frames, nil)
+	results := run(sd, clk, len(frames), 100*time.Millisecond)
+
+	if got := countBehaviors(results, "MOVIMENTO_SUSPEITO", false); got != 3 {
+		t.Errorf("alertas de movimento = %d, esperado 3", got)
+	}
+}
+
+func TestTrackLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracker string
+		present int // frames com a pessoa visível
+		absent  int // frames seguintes sem detecções
+		want    int // pessoas rastreadas ao final
+	}{
+		{name: "sort: tentativa não vira pessoa", tracker: "sort", present: 2, want: 0},
+		{name: "sort: confirmada após min hits", tracker: "sort", present: 3, want: 1},
+		{name: "sort: mantida antes do timeout", tracker: "sort", present: 5, absent: 40, want: 1},
+		{name: "sort: expira após o timeout", tracker: "sort", present: 5, absent: 60, want: 0},
+		{name: "centroid: confirmada no primeiro frame", tracker: "centroid", present: 1, want: 1},
+		{name: "centroid: expira após o timeout", tracker: "centroid", present: 5, absent: 60, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := append(stationary(tt.present), empty(tt.absent)...)
+			sd, clk := newTestDetector(t, frames, func(cfg *config.Config) {
+				cfg.TrackerType = tt.tracker
+			})
+			run(sd, clk, len(frames), 100*time.Millisecond)
+
+			if got := sd.TrackingStats().TrackedPeople; got != tt.want {
+				t.Errorf("pessoas rastreadas = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogThrottling(t *testing.T) {
+	tests := []struct {
+		name      string
+		step      time.Duration
+		frames    int
+		wantLogs  int
+		wantTotal int
+	}{
+		// Permanência acima de 20s: de 20.1s a 29.9s, um log por segundo
+		{name: "10 fps", step: 100 * time.Millisecond, frames: 300, wantLogs: 10, wantTotal: 99},
+		// Frames a cada 2s: todo alerta pode ser logado
+		{name: "frames espaçados", step: 2 * time.Second, frames: 15, wantLogs: 4, wantTotal: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, clk := newTestDetector(t, stationary(tt.frames), nil)
+			results := run(sd, clk, tt.frames, tt.step)
+
+			if got := countBehaviors(results, "PERMANENCIA_EXCESSIVA", false); got != tt.wantTotal {
+				t.Errorf("comportamentos = %d, esperado %d", got, tt.wantTotal)
+			}
+			if got := countBehaviors(results, "PERMANENCIA_EXCESSIVA", true); got != tt.wantLogs {
+				t.Errorf("logs = %d, esperado %d", got, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestDetectionStride(t *testing.T) {
+	const stride = 3
+	frames := stationary(250) // ~25s
+	sd, clk := newTestDetector(t, nil, nil)
+
+	var results [][]SuspiciousBehavior
+	for i, detections := range frames {
+		if i > 0 {
+			clk.Advance(100 * time.Millisecond)
+		}
+		// Detecção a cada 3 frames; nos demais, a análise segue o tracker
+		var behaviors []SuspiciousBehavior
+		var boxes []DetectionResult
+		if i%stride == 0 {
+			boxes, behaviors = sd.Analyze(detections)
+		} else {
+			boxes, behaviors = sd.Interpolate()
+		}
+		results = append(results, behaviors)
+
+		// A pessoa confirmada continua visível nos frames interpolados
+		if i >= 2*stride && len(boxes) != 1 {
+			t.Fatalf("frame %d: %d caixas, esperado 1", i, len(boxes))
+		}
+	}
+
+	if got := sd.TrackingStats().TrackedPeople; got != 1 {
+		t.Errorf("pessoas rastreadas = %d, esperado 1", got)
+	}
+	// Permanência avaliada a cada frame, inclusive nos interpolados
+	if got := countBehaviors(results, "PERMANENCIA_EXCESSIVA", false); got != 49 {
+		t.Errorf("comportamentos = %d, esperado 49", got)
+	}
+}
+
+func TestRiskScore(t *testing.T) {
+	// 15s perto do celular, depois 20s longe dele
+	frames := script(350, func(i int) []DetectionResult {
+		if i < 150 {
+			return []DetectionResult{person(100, 100), phone(150, 170)}
+		}
+		return []DetectionResult{person(100, 100)}
+	})
+
+	tests := []struct {
+		name      string
+		threshold float64
+		wantCount int
+	}{
+		{name: "alerta ao cruzar o limite", threshold: 0.7, wantCount: 1},
+		{name: "limite zero desabilita o alerta", threshold: 0, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, clk := newTestDetector(t, frames, func(cfg *config.Config) {
+				cfg.LoiteringTimeThreshold = 60 // só a proximidade pontua
+				cfg.RiskWeightProximity = 0.5
+				cfg.RiskThreshold = tt.threshold
+			})
+
+			var peak float64
+			results := make([][]SuspiciousBehavior, len(frames))
+			for i := range frames {
+				if i > 0 {
+					clk.Advance(100 * time.Millisecond)
+				}
+				_, results[i] = sd.DetectShoplifting(gocv.Mat{})
+				for _, tracked := range sd.trackedPeople {
+					peak = max(peak, tracked.RiskScore)
+				}
+			}
+
+			if len(sd.trackedPeople) != 1 {
+				t.Fatalf("pessoas rastreadas = %d, esperado 1", len(sd.trackedPeople))
+			}
+			var tracked *TrackedPerson
+			for _, p := range sd.trackedPeople {
+				tracked = p
+			}
+
+			if peak < 0.7 {
+				t.Errorf("pontuação máxima = %.2f, esperado >= 0.7", peak)
+			}
+			// 20s sem proximidade: duas meias-vidas de decaimento
+			if tracked.RiskScore > peak/3 {
+				t.Errorf("pontuação final = %.2f, esperado decaimento a partir de %.2f", tracked.RiskScore, peak)
+			}
+			if tracked.SuspiciousCount != tt.wantCount {
+				t.Errorf("episódios suspeitos = %d, esperado %d", tracked.SuspiciousCount, tt.wantCount)
+			}
+			if got := countBehaviors(results, "RISCO_ELEVADO", false) > 0; got != (tt.wantCount > 0) {
+				t.Errorf("RISCO_ELEVADO detectado = %v", got)
+			}
+		})
+	}
+}
+
+func TestRiskOverlayWithSystemClock(t *testing.T) {
+	// Relógio de parede (câmeras e streams): cada Now é um instante novo
+	frames := append(stationary(5), empty(2)...)
+	sd, _ := newTestDetector(t, frames, nil)
+	sd.SetClock(clock.System{})
+
+	for i := 0; i < 5; i++ {
+		sd.DetectShoplifting(gocv.Mat{})
+	}
+	if got := len(sd.seenInLastFrame()); got != 1 {
+		t.Fatalf("pessoas no overlay de risco = %d, esperado 1", got)
+	}
+
+	// Sem detecção, a pessoa segue rastreada mas não foi vista neste frame
+	sd.DetectShoplifting(gocv.Mat{})
+	if len(sd.trackedPeople) != 1 {
+		t.Fatalf("pessoas rastreadas = %d, esperado 1", len(sd.trackedPeople))
+	}
+	if got := len(sd.seenInLastFrame()); got != 0 {
+		t.Errorf("pessoas no overlay de risco após frame vazio = %d, esperado 0", got)
+	}
+}
@@ -0,0 +1,227 @@
+package shoplifting
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/config"
+)
+
+// zone é uma zona da configuração com o polígono pronto para testes de ponto
+type zone struct {
+	config.Zone
+	polygon        []image.Point
+	dwellThreshold float64 // segundos
+}
+
+// ZonePresence registra a presença de uma pessoa em uma zona
+type ZonePresence struct {
+	Zone    string
+	Type    string
+	Entered time.Time
+}
+
+// newZones prepara as zonas configuradas
+func newZones(cfgZones []config.Zone, defaultDwell float64) []zone {
+	zones := make([]zone, 0, len(cfgZones))
+	for _, z := range cfgZones {
+		polygon := make([]image.Point, len(z.Points))
+		for i, p := range z.Points {
+			polygon[i] = image.Pt(p[0], p[1])
+		}
+
+		dwell := z.DwellThreshold
+		if dwell == 0 {
+			dwell = defaultDwell
+		}
+		zones = append(zones, zone{Zone: z, polygon: polygon, dwellThreshold: dwell})
+	}
+	return zones
+}
+
+// contains verifica se o ponto está dentro do polígono (ray casting)
+func (z zone) contains(p image.Point) bool {
+	inside := false
+	n := len(z.polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := z.polygon[i], z.polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			crossX := float64(b.X-a.X)*float64(p.Y-a.Y)/float64(b.Y-a.Y) + float64(a.X)
+			if float64(p.X) < crossX {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// isDwellZone indica se a zona conta tempo de permanência
+func (z zone) isDwellZone() bool {
+	return z.Type == config.ZoneShelf || z.Type == config.ZoneHighValue
+}
+
+// footPoint retorna o ponto de apoio da pessoa (centro da base da caixa),
+// que representa melhor a posição no chão da loja que o centro da caixa
+func footPoint(box image.Rectangle) image.Point {
+	return image.Pt(box.Min.X+box.Dx()/2, box.Max.Y)
+}
+
+// hasDwellZones indica se a permanência deve ser medida por zona
+func (sd *ShopliftingDetector) hasDwellZones() bool {
+	for _, z := range sd.zones {
+		if z.isDwellZone() {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMasked descarta detecções cujo centro está em uma zona ignore
+func (sd *ShopliftingDetector) filterMasked(detections []DetectionResult) []DetectionResult {
+	var masks []zone
+	for _, z := range sd.zones {
+		if z.Type == config.ZoneIgnore {
+			masks = append(masks, z)
+		}
+	}
+	if len(masks) == 0 {
+		return detections
+	}
+
+	var kept []DetectionResult
+	for _, det := range detections {
+		center := image.Pt(det.Box.Min.X+det.Box.Dx()/2, det.Box.Min.Y+det.Box.Dy()/2)
+		masked := false
+		for _, m := range masks {
+			if m.contains(center) {
+				masked = true
+				break
+			}
+		}
+		if !masked {
+			kept = append(kept, det)
+		}
+	}
+	return kept
+}
+
+// updateZones atualiza as zonas em que a pessoa está e as visitadas
+func (sd *ShopliftingDetector) updateZones(tracked *TrackedPerson, box image.Rectangle, currentTime time.Time) {
+	if len(sd.zones) == 0 {
+		return
+	}
+
+	foot := footPoint(box)
+	for _, z := range sd.zones {
+		if z.Type == config.ZoneIgnore {
+			continue
+		}
+
+		if !z.contains(foot) {
+			// Saiu da zona: a permanência recomeça na próxima entrada
+			delete(tracked.Zones, z.Name)
+			continue
+		}
+
+		if _, inside := tracked.Zones[z.Name]; !inside {
+			tracked.Zones[z.Name] = ZonePresence{Zone: z.Name, Type: z.Type, Entered: currentTime}
+		}
+		tracked.VisitedZoneTypes[z.Type] = true
+	}
+}
+
+// analyzeZones gera comportamentos que dependem da geometria da loja
+func (sd *ShopliftingDetector) analyzeZones(tracked *TrackedPerson, location image.Point, currentTime time.Time) []SuspiciousBehavior {
+	var behaviors []SuspiciousBehavior
+
+	for _, z := range sd.zones {
+		presence, inside := tracked.Zones[z.Name]
+		if !inside {
+			continue
+		}
+		dwell := currentTime.Sub(presence.Entered).Seconds()
+
+		switch {
+		case z.isDwellZone() && dwell > z.dwellThreshold:
+			// Permanência medida dentro da zona, não no frame inteiro
+			behaviors = append(behaviors, SuspiciousBehavior{
+				Type:        "PERMANENCIA_EXCESSIVA",
+				Confidence:  float32(math.Min(dwell/(z.dwellThreshold*1.5), 1.0)),
+				Description: fmt.Sprintf("Pessoa permanecendo em %s por %.1f segundos", z.Name, dwell),
+				Details:     fmt.Sprintf("Zona: %s (%s) | Limite: %.1fs | Tempo atual: %.1fs", z.Name, z.Type, z.dwellThreshold, dwell),
+				PersonID:    tracked.ID,
+				Location:    location,
+				ShouldLog:   sd.shouldLogBehavior(tracked, "PERMANENCIA_EXCESSIVA_"+z.Name),
+			})
+
+		case z.Type == config.ZoneStaffOnly:
+			behaviors = append(behaviors, SuspiciousBehavior{
+				Type:        "ACESSO_RESTRITO",
+				Confidence:  0.9,
+				Description: fmt.Sprintf("Pessoa em área restrita: %s", z.Name),
+				Details:     fmt.Sprintf("Zona: %s | Tempo na zona: %.1fs", z.Name, dwell),
+				PersonID:    tracked.ID,
+				Location:    location,
+				ShouldLog:   sd.shouldLogBehavior(tracked, "ACESSO_RESTRITO_"+z.Name),
+			})
+
+		case z.Type == config.ZoneExit && !tracked.ExitAlerted && sd.exitWithoutCheckout(tracked):
+			// Alerta único por pessoa
+			tracked.ExitAlerted = true
+			behaviors = append(behaviors, SuspiciousBehavior{
+				Type:        "SAIDA_SEM_CAIXA",
+				Confidence:  0.85,
+				Description: fmt.Sprintf("Pessoa entrou em %s sem passar pelo caixa", z.Name),
+				Details:     fmt.Sprintf("Zona: %s | Tempo na loja: %.1fs", z.Name, currentTime.Sub(tracked.FirstSeen).Seconds()),
+				PersonID:    tracked.ID,
+				Location:    location,
+				ShouldLog:   true,
+			})
+		}
+	}
+
+	return behaviors
+}
+
+// exitWithoutCheckout verifica se a pessoa chegou à saída sem visitar o caixa.
+// Quando há zonas de produtos configuradas, exige que a pessoa tenha passado
+// por uma delas (quem só atravessa a porta de entrada não gera alerta).
+func (sd *ShopliftingDetector) exitWithoutCheckout(tracked *TrackedPerson) bool {
+	if tracked.VisitedZoneTypes[config.ZoneCheckout] {
+		return false
+	}
+
+	if !sd.hasDwellZones() {
+		return true
+	}
+	return tracked.VisitedZoneTypes[config.ZoneShelf] || tracked.VisitedZoneTypes[config.ZoneHighValue]
+}
+
+// zoneColors define a cor de cada tipo de zona no overlay
+var zoneColors = map[string]color.RGBA{
+	config.ZoneShelf:     {0, 200, 255, 255},
+	config.ZoneHighValue: {255, 165, 0, 255},
+	config.ZoneCheckout:  {0, 255, 0, 255},
+	config.ZoneExit:      {255, 0, 255, 255},
+	config.ZoneStaffOnly: {255, 0, 0, 255},
+	config.ZoneIgnore:    {128, 128, 128, 255},
+}
+
+// DrawZones desenha os polígonos das zonas configuradas
+func (sd *ShopliftingDetector) DrawZones(img *gocv.Mat) {
+	for _, z := range sd.zones {
+		zoneColor := zoneColors[z.Type]
+
+		points := gocv.NewPointsVectorFromPoints([][]image.Point{z.polygon})
+		gocv.Polylines(img, points, true, zoneColor, 2)
+		points.Close()
+
+		gocv.PutText(img, z.Name,
+			image.Pt(z.polygon[0].X+5, z.polygon[0].Y+20),
+			gocv.FontHersheySimplex, 0.5, zoneColor, 1)
+	}
+}
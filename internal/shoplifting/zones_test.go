@@ -0,0 +1,255 @@
+package shoplifting
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"poc-camera/config"
+)
+
+// rect cria uma zona retangular com os cantos (x1, y1) e (x2, y2)
+func rect(name, zoneType string, x1, y1, x2, y2 int) config.Zone {
+	return config.Zone{
+		Name:   name,
+		Type:   zoneType,
+		Points: [][2]int{{x1, y1}, {x2, y1}, {x2, y2}, {x1, y2}},
+	}
+}
+
+func TestZoneContains(t *testing.T) {
+	// Polígono em L (côncavo)
+	l := newZones([]config.Zone{{
+		Name:   "L",
+		Type:   config.ZoneShelf,
+		Points: [][2]int{{0, 0}, {100, 0}, {100, 40}, {40, 40}, {40, 100}, {0, 100}},
+	}}, 0)[0]
+	triangle := newZones([]config.Zone{{
+		Name:   "triângulo",
+		Type:   config.ZoneExit,
+		Points: [][2]int{{0, 0}, {100, 0}, {0, 100}},
+	}}, 0)[0]
+
+	tests := []struct {
+		name  string
+		zone  zone
+		point image.Point
+		want  bool
+	}{
+		{name: "L: braço horizontal", zone: l, point: image.Pt(70, 20), want: true},
+		{name: "L: braço vertical", zone: l, point: image.Pt(20, 70), want: true},
+		{name: "L: canto côncavo", zone: l, point: image.Pt(70, 70), want: false},
+		{name: "L: fora à direita", zone: l, point: image.Pt(150, 20), want: false},
+		{name: "L: acima", zone: l, point: image.Pt(20, -5), want: false},
+		{name: "triângulo: dentro", zone: triangle, point: image.Pt(20, 20), want: true},
+		{name: "triângulo: além da hipotenusa", zone: triangle, point: image.Pt(60, 60), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zone.contains(tt.point); got != tt.want {
+				t.Errorf("contains(%v) = %v, esperado %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewZonesDwellThreshold(t *testing.T) {
+	zones := newZones([]config.Zone{
+		{Name: "própria", Type: config.ZoneShelf, DwellThreshold: 5},
+		{Name: "padrão", Type: config.ZoneShelf},
+	}, 20)
+
+	if zones[0].dwellThreshold != 5 || zones[1].dwellThreshold != 20 {
+		t.Errorf("limites = %.0f/%.0f, esperado 5/20", zones[0].dwellThreshold, zones[1].dwellThreshold)
+	}
+}
+
+func TestFootPoint(t *testing.T) {
+	// person(100, 100) ocupa (100,100)-(160,250): pés no centro da base
+	if got := footPoint(person(100, 100).Box); got != image.Pt(130, 250) {
+		t.Errorf("ponto de apoio = %v, esperado (130,250)", got)
+	}
+}
+
+func TestZoneBehaviors(t *testing.T) {
+	// A pessoa fica parada em person(100, 100): centro (130,175), pés (130,250).
+	// As zonas abaixo contêm os pés mas não o centro da caixa, e vice-versa.
+	aroundFeet := func(name, zoneType string) config.Zone {
+		return rect(name, zoneType, 80, 220, 180, 280)
+	}
+	aroundCenter := func(name, zoneType string) config.Zone {
+		return rect(name, zoneType, 80, 140, 180, 200)
+	}
+
+	tests := []struct {
+		name     string
+		zones    []config.Zone
+		frames   int // a 100 ms
+		behavior string
+		want     int // frames com o comportamento
+	}{
+		{
+			name:     "permanência medida na zona com limite próprio",
+			zones:    []config.Zone{withDwell(aroundFeet("gôndola", config.ZoneShelf), 5)},
+			frames:   80, // entra na zona ao confirmar a trilha (0.2s): acima de 5s de 5.3s a 7.9s
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     27,
+		},
+		{
+			name:     "zona sem limite usa loitering_time_threshold",
+			zones:    []config.Zone{aroundFeet("gôndola", config.ZoneShelf)},
+			frames:   80,
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     0,
+		},
+		{
+			// Com zonas de permanência, o tempo no frame inteiro não conta
+			name:     "fora das zonas de permanência",
+			zones:    []config.Zone{withDwell(rect("gôndola", config.ZoneShelf, 400, 0, 500, 100), 5)},
+			frames:   250,
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     0,
+		},
+		{
+			// A zona é testada pelos pés, não pelo centro da caixa
+			name:     "centro da caixa na zona, pés fora",
+			zones:    []config.Zone{withDwell(aroundCenter("gôndola", config.ZoneShelf), 5)},
+			frames:   80,
+			behavior: "PERMANENCIA_EXCESSIVA",
+			want:     0,
+		},
+		{
+			name:     "área restrita",
+			zones:    []config.Zone{aroundFeet("estoque", config.ZoneStaffOnly)},
+			frames:   10,
+			behavior: "ACESSO_RESTRITO",
+			want:     8, // desde a confirmação da trilha, no terceiro frame
+		},
+		{
+			name:     "fora da área restrita",
+			zones:    []config.Zone{rect("estoque", config.ZoneStaffOnly, 400, 0, 500, 100)},
+			frames:   10,
+			behavior: "ACESSO_RESTRITO",
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, clk := newTestDetector(t, stationary(tt.frames), func(cfg *config.Config) {
+				cfg.Zones = tt.zones
+			})
+			results := run(sd, clk, tt.frames, 100*time.Millisecond)
+
+			if got := countBehaviors(results, tt.behavior, false); got != tt.want {
+				t.Errorf("frames com %s = %d, esperado %d", tt.behavior, got, tt.want)
+			}
+			for _, frame := range results {
+				for _, b := range frame {
+					if b.Type == tt.behavior && !strings.Contains(b.Description, tt.zones[0].Name) {
+						t.Errorf("descrição %q não cita a zona %s", b.Description, tt.zones[0].Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+// withDwell define o limite de permanência da zona
+func withDwell(z config.Zone, seconds float64) config.Zone {
+	z.DwellThreshold = seconds
+	return z
+}
+
+func TestIgnoreMask(t *testing.T) {
+	tests := []struct {
+		name       string
+		mask       config.Zone
+		wantPeople int
+		wantNear   bool
+	}{
+		{
+			name:       "sem máscara",
+			mask:       rect("vitrine", config.ZoneIgnore, 600, 600, 700, 700),
+			wantPeople: 1,
+			wantNear:   true,
+		},
+		{
+			// O celular em phone(150, 170) tem centro (165,185)
+			name:       "item valioso na máscara",
+			mask:       rect("vitrine", config.ZoneIgnore, 150, 180, 200, 200),
+			wantPeople: 1,
+			wantNear:   false,
+		},
+		{
+			// Manequim: a pessoa (centro (130,175)) é descartada
+			name:       "pessoa na máscara",
+			mask:       rect("manequim", config.ZoneIgnore, 100, 150, 140, 200),
+			wantPeople: 0,
+			wantNear:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := script(10, func(int) []DetectionResult {
+				return []DetectionResult{person(100, 100), phone(150, 170)}
+			})
+			sd, clk := newTestDetector(t, frames, func(cfg *config.Config) {
+				cfg.Zones = []config.Zone{tt.mask}
+			})
+			results := run(sd, clk, len(frames), 100*time.Millisecond)
+
+			if got := len(sd.trackedPeople); got != tt.wantPeople {
+				t.Errorf("pessoas rastreadas = %d, esperado %d", got, tt.wantPeople)
+			}
+			if got := countBehaviors(results, "PROXIMIDADE_SUSPEITA", false) > 0; got != tt.wantNear {
+				t.Errorf("PROXIMIDADE_SUSPEITA detectado = %v, esperado %v", got, tt.wantNear)
+			}
+		})
+	}
+}
+
+func TestExitWithoutCheckout(t *testing.T) {
+	// A pessoa atravessa a loja da esquerda para a direita na faixa dos pés
+	// (y 250) e fica parada na saída: prateleira x < 200, caixa 200-400,
+	// saída > 400
+	frames := script(40, func(i int) []DetectionResult {
+		return []DetectionResult{person(min(20*i, 500), 100)}
+	})
+	shelf := rect("gôndola", config.ZoneShelf, 0, 200, 200, 300)
+	checkout := rect("caixa", config.ZoneCheckout, 200, 200, 400, 300)
+	checkoutAside := rect("caixa", config.ZoneCheckout, 200, 400, 400, 500)
+	exit := rect("porta", config.ZoneExit, 400, 200, 600, 300)
+	entrance := rect("gôndola", config.ZoneShelf, 0, 400, 200, 500)
+
+	tests := []struct {
+		name  string
+		zones []config.Zone
+		want  int
+	}{
+		{name: "prateleira, caixa e saída", zones: []config.Zone{shelf, checkout, exit}, want: 0},
+		{name: "prateleira e saída sem caixa", zones: []config.Zone{shelf, checkoutAside, exit}, want: 1},
+		{name: "só atravessa a loja sem passar por produtos", zones: []config.Zone{entrance, checkoutAside, exit}, want: 0},
+		{name: "sem zonas de produtos", zones: []config.Zone{checkoutAside, exit}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, clk := newTestDetector(t, frames, func(cfg *config.Config) {
+				cfg.Zones = tt.zones
+			})
+			results := run(sd, clk, len(frames), 100*time.Millisecond)
+
+			if len(sd.trackedPeople) != 1 {
+				t.Fatalf("pessoas rastreadas = %d, esperado 1 (a trilha se perdeu no caminho)", len(sd.trackedPeople))
+			}
+			// O alerta é único mesmo com a pessoa parada na saída
+			if got := countBehaviors(results, "SAIDA_SEM_CAIXA", true); got != tt.want {
+				t.Errorf("alertas SAIDA_SEM_CAIXA = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package source
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/internal/logging"
+)
+
+// Tipos de fonte suportados
+const (
+	TypeAuto   = "auto"
+	TypeDevice = "device"
+	TypeFile   = "file"
+	TypeStream = "stream"
+	TypeImages = "images"
+)
+
+// maxProbedDevices é a quantidade de índices de câmera testados no modo automático
+const maxProbedDevices = 4
+
+// imageExtensions lista as extensões aceitas no modo diretório de imagens
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".bmp":  true,
+}
+
+// FrameSource abstrai uma origem de frames (webcam, arquivo, stream ou diretório)
+type FrameSource interface {
+	// Read lê o próximo frame em img; retorna false quando não há mais frames
+	Read(img *gocv.Mat) bool
+	// Name descreve a fonte para logs
+	Name() string
+	// Timestamp retorna a posição do último frame lido na mídia (PTS);
+	// false quando a fonte não informa timestamps confiáveis
+	Timestamp() (time.Duration, bool)
+	// Live indica se a fonte é ao vivo (câmera ou stream)
+	Live() bool
+	// Close libera os recursos da fonte
+	Close() error
+}
+
+// Open abre uma fonte de frames a partir da especificação configurada.
+// Sem tipo explícito (auto), o tipo é inferido da especificação:
+// vazio ou "auto" testa as câmeras 0-3, um número é índice de câmera,
+// uma URL (rtsp://, http://...) é stream, um diretório é sequência de
+// imagens e qualquer outro caminho é arquivo de vídeo.
+func Open(sourceType, spec string) (FrameSource, error) {
+	if sourceType == "" || sourceType == TypeAuto {
+		sourceType = inferType(spec)
+	}
+
+	switch sourceType {
+	case TypeAuto:
+		return probeDevices()
+	case TypeDevice:
+		index, err := strconv.Atoi(spec)
+		if err != nil {
+			return nil, fmt.Errorf("índice de câmera inválido: %q", spec)
+		}
+		return openDevice(index)
+	case TypeFile:
+		if _, err := os.Stat(spec); err != nil {
+			return nil, fmt.Errorf("arquivo de vídeo inválido: %v", err)
+		}
+		return openCapture(spec, fmt.Sprintf("arquivo %s", spec), false)
+	case TypeStream:
+		return openCapture(spec, fmt.Sprintf("stream %s", spec), true)
+	case TypeImages:
+		return openImageDir(spec)
+	default:
+		return nil, fmt.Errorf("tipo de fonte desconhecido: %q", sourceType)
+	}
+}
+
+// inferType deduz o tipo da fonte a partir da especificação
+func inferType(spec string) string {
+	if spec == "" || spec == TypeAuto {
+		return TypeAuto
+	}
+	if _, err := strconv.Atoi(spec); err == nil {
+		return TypeDevice
+	}
+	if strings.Contains(spec, "://") {
+		return TypeStream
+	}
+	if info, err := os.Stat(spec); err == nil && info.IsDir() {
+		return TypeImages
+	}
+	return TypeFile
+}
+
+// captureSource adapta gocv.VideoCapture (câmera, arquivo ou stream)
+type captureSource struct {
+	capture *gocv.VideoCapture
+	name    string
+	live    bool
+}
+
+// Read implementa FrameSource
+func (s *captureSource) Read(img *gocv.Mat) bool {
+	return s.capture.Read(img)
+}
+
+// Name implementa FrameSource
+func (s *captureSource) Name() string {
+	return s.name
+}
+
+// Timestamp implementa FrameSource. Só arquivos têm PTS confiável; em
+// câmeras e streams a posição reportada pelo OpenCV varia com o backend.
+func (s *captureSource) Timestamp() (time.Duration, bool) {
+	if s.live {
+		return 0, false
+	}
+	msec := s.capture.Get(gocv.VideoCapturePosMsec)
+	if msec < 0 {
+		return 0, false
+	}
+	return time.Duration(msec * float64(time.Millisecond)), true
+}
+
+// Live implementa FrameSource
+func (s *captureSource) Live() bool {
+	return s.live
+}
+
+// Close implementa FrameSource
+func (s *captureSource) Close() error {
+	return s.capture.Close()
+}
+
+// openCapture abre arquivo ou stream via OpenCV
+func openCapture(uri, name string, live bool) (FrameSource, error) {
+	capture, err := gocv.VideoCaptureFile(uri)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir %s: %v", name, err)
+	}
+	if !capture.IsOpened() {
+		capture.Close()
+		return nil, fmt.Errorf("erro ao abrir %s", name)
+	}
+	return &captureSource{capture: capture, name: name, live: live}, nil
+}
+
+// openDevice abre uma câmera e verifica se ela captura frames
+func openDevice(index int) (FrameSource, error) {
+	webcam, err := gocv.VideoCaptureDevice(index)
+	if err != nil {
+		return nil, fmt.Errorf("câmera %d: %v", index, err)
+	}
+
+	// Testa se consegue capturar um frame
+	testImg := gocv.NewMat()
+	defer testImg.Close()
+	if ok := webcam.Read(&testImg); !ok || testImg.Empty() {
+		webcam.Close()
+		return nil, fmt.Errorf("câmera %d não consegue capturar frames", index)
+	}
+
+	return &captureSource{capture: webcam, name: fmt.Sprintf("câmera %d", index), live: true}, nil
+}
+
+// probeDevices tenta os primeiros índices de câmera até encontrar um funcional
+func probeDevices() (FrameSource, error) {
+	for i := 0; i < maxProbedDevices; i++ {
+		slog.Info("Tentando câmera", logging.Icon("🔍"), slog.Int("index", i))
+		src, err := openDevice(i)
+		if err != nil {
+			slog.Warn("Câmera indisponível", logging.Icon("❌"), slog.Int("index", i), logging.Err(err))
+			continue
+		}
+		slog.Info("Câmera funcionando", logging.Icon("✅"), slog.Int("index", i))
+		return src, nil
+	}
+
+	return nil, fmt.Errorf("nenhuma câmera funcional encontrada (testados índices 0-%d)", maxProbedDevices-1)
+}
+
+// imageDirSource lê imagens de um diretório em ordem alfabética
+type imageDirSource struct {
+	dir   string
+	files []string
+	next  int
+}
+
+// openImageDir lista as imagens do diretório
+func openImageDir(dir string) (FrameSource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler diretório de imagens: %v", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if imageExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nenhuma imagem encontrada em %s", dir)
+	}
+	sort.Strings(files)
+
+	return &imageDirSource{dir: dir, files: files}, nil
+}
+
+// Read implementa FrameSource
+func (s *imageDirSource) Read(img *gocv.Mat) bool {
+	for s.next < len(s.files) {
+		path := s.files[s.next]
+		s.next++
+
+		frame := gocv.IMRead(path, gocv.IMReadColor)
+		if frame.Empty() {
+			// Imagens ilegíveis são puladas
+			frame.Close()
+			slog.Warn("Imagem ilegível ignorada", slog.String("path", path))
+			continue
+		}
+		frame.CopyTo(img)
+		frame.Close()
+		return true
+	}
+	return false
+}
+
+// Name implementa FrameSource
+func (s *imageDirSource) Name() string {
+	return fmt.Sprintf("diretório %s (%d imagens)", s.dir, len(s.files))
+}
+
+// Timestamp implementa FrameSource (imagens não têm timestamp)
+func (s *imageDirSource) Timestamp() (time.Duration, bool) {
+	return 0, false
+}
+
+// Live implementa FrameSource
+func (s *imageDirSource) Live() bool {
+	return false
+}
+
+// Close implementa FrameSource
+func (s *imageDirSource) Close() error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package tracking
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// CentroidTracker associa cada detecção à trilha cujo último centro está mais
+// próximo (busca gulosa limitada por ProximityThreshold). Mantido como
+// referência para comparar com o SORTTracker: duas detecções podem disputar
+// a mesma trilha e os IDs trocam quando pessoas se cruzam.
+type CentroidTracker struct {
+	opts   Options
+	tracks map[int]*Track
+	nextID int
+	stats  Stats
+}
+
+// NewCentroidTracker cria um tracker por centroide mais próximo
+func NewCentroidTracker(opts Options) *CentroidTracker {
+	return &CentroidTracker{
+		opts:   opts,
+		tracks: make(map[int]*Track),
+		nextID: 1,
+	}
+}
+
+// Update implementa Tracker
+func (ct *CentroidTracker) Update(detections []Detection, now time.Time) []Track {
+	for _, track := range ct.tracks {
+		track.Detection = -1
+	}
+
+	for i, det := range detections {
+		center := image.Pt(det.Box.Min.X+det.Box.Dx()/2, det.Box.Min.Y+det.Box.Dy()/2)
+		track := ct.findNearest(center)
+		if track == nil {
+			if !ct.makeRoom(det.Confidence) {
+				continue
+			}
+			track = &Track{ID: ct.nextID, FirstSeen: now}
+			ct.nextID++
+			ct.stats.Created++
+			ct.tracks[track.ID] = track
+		}
+
+		track.Box = det.Box
+		track.Confidence = det.Confidence
+		track.State = StateConfirmed
+		track.Hits++
+		track.LastSeen = now
+		track.Detection = i
+	}
+
+	result := make([]Track, 0, len(ct.tracks))
+	for id, track := range ct.tracks {
+		if track.Detection == -1 {
+			if now.Sub(track.LastSeen) > ct.opts.MaxAge {
+				delete(ct.tracks, id)
+				continue
+			}
+			track.State = StateLost
+		}
+		result = append(result, *track)
+	}
+	ct.stats.Active = len(ct.tracks)
+	return result
+}
+
+// Predict implementa Tracker: sem modelo de movimento, as trilhas mantêm a
+// última caixa
+func (ct *CentroidTracker) Predict(now time.Time) []Track {
+	result := make([]Track, 0, len(ct.tracks))
+	for _, track := range ct.tracks {
+		track.Detection = -1
+		result = append(result, *track)
+	}
+	return result
+}
+
+// Stats implementa Tracker
+func (ct *CentroidTracker) Stats() Stats {
+	return ct.stats
+}
+
+// makeRoom remove uma trilha se o limite foi atingido.
+// Retorna false se a detecção nova não deve criar trilha.
+func (ct *CentroidTracker) makeRoom(confidence float32) bool {
+	if ct.opts.MaxTracks <= 0 || len(ct.tracks) < ct.opts.MaxTracks {
+		return true
+	}
+
+	// Trilhas já associadas neste frame não são candidatas
+	var tracks []*Track
+	for _, track := range ct.tracks {
+		if track.Detection == -1 {
+			tracks = append(tracks, track)
+		}
+	}
+	victim := evictionCandidate(tracks, ct.opts.EvictionPolicy, confidence)
+	if victim == -1 {
+		return false
+	}
+
+	delete(ct.tracks, tracks[victim].ID)
+	ct.stats.Evictions++
+	return true
+}
+
+// findNearest encontra a trilha com último centro mais próximo dentro do limite
+func (ct *CentroidTracker) findNearest(center image.Point) *Track {
+	minDistance := ct.opts.ProximityThreshold
+	var nearest *Track
+
+	for _, track := range ct.tracks {
+		distance := pointDistance(center, track.Center())
+		if distance < minDistance {
+			minDistance = distance
+			nearest = track
+		}
+	}
+
+	return nearest
+}
+
+// pointDistance calcula a distância euclidiana entre dois pontos
+func pointDistance(a, b image.Point) float64 {
+	dx := float64(a.X - b.X)
+	dy := float64(a.Y - b.Y)
+	return math.Sqrt(dx*dx + dy*dy)
+}
@@ -0,0 +1,105 @@
+package tracking
+
+import "math"
+
+// Assign resolve o problema de atribuição de custo mínimo (algoritmo húngaro,
+// O(n³)) para uma matriz de custo retangular linhas x colunas. Retorna, para
+// cada linha, a coluna atribuída ou -1. Custos +Inf marcam pares proibidos,
+// que nunca são atribuídos.
+func Assign(cost [][]float64) []int {
+	rows := len(cost)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(cost[0])
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	if cols == 0 {
+		return assignment
+	}
+
+	// Pares proibidos recebem um custo finito maior que qualquer solução válida
+	forbidden := 1.0
+	for _, row := range cost {
+		for _, c := range row {
+			if !math.IsInf(c, 1) {
+				forbidden += math.Abs(c)
+			}
+		}
+	}
+
+	// Matriz quadrada (1-indexada) preenchida com o custo proibido
+	n := max(rows, cols)
+	a := make([][]float64, n+1)
+	for i := 1; i <= n; i++ {
+		a[i] = make([]float64, n+1)
+		for j := 1; j <= n; j++ {
+			a[i][j] = forbidden
+			if i <= rows && j <= cols && !math.IsInf(cost[i-1][j-1], 1) {
+				a[i][j] = cost[i-1][j-1]
+			}
+		}
+	}
+
+	// Potenciais u/v e caminhos aumentantes (variante de Jonker-Volgenant)
+	u := make([]float64, n+1)
+	v := make([]float64, n+1)
+	p := make([]int, n+1) // p[j] = linha atribuída à coluna j
+	way := make([]int, n+1)
+
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, n+1)
+		used := make([]bool, n+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+
+		for p[j0] != 0 {
+			used[j0] = true
+			i0 := p[j0]
+			delta := math.Inf(1)
+			j1 := 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				cur := a[i0][j] - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	for j := 1; j <= n; j++ {
+		i := p[j]
+		if i >= 1 && i <= rows && j <= cols && !math.IsInf(cost[i-1][j-1], 1) {
+			assignment[i-1] = j - 1
+		}
+	}
+	return assignment
+}
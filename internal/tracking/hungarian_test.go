@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAssign(t *testing.T) {
+	inf := math.Inf(1)
+
+	tests := []struct {
+		name string
+		cost [][]float64
+		want []int
+	}{
+		{
+			name: "vazia",
+			cost: nil,
+			want: nil,
+		},
+		{
+			name: "sem colunas",
+			cost: [][]float64{{}, {}},
+			want: []int{-1, -1},
+		},
+		{
+			// O guloso pegaria (0,0) e (1,1), custo 11; o ótimo custa 3
+			name: "ótimo diferente do guloso",
+			cost: [][]float64{
+				{1, 2},
+				{1, 10},
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "mais linhas que colunas",
+			cost: [][]float64{
+				{5, 9},
+				{1, 8},
+				{4, 2},
+			},
+			want: []int{-1, 0, 1},
+		},
+		{
+			name: "mais colunas que linhas",
+			cost: [][]float64{
+				{7, 3, 6},
+				{2, 4, 5},
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "par proibido fica sem atribuição",
+			cost: [][]float64{
+				{1, inf},
+				{inf, inf},
+			},
+			want: []int{0, -1},
+		},
+		{
+			// Atribuir a linha 0 à coluna 0 deixaria a linha 1 sem par
+			name: "proibição força a outra coluna",
+			cost: [][]float64{
+				{0.1, 0.5},
+				{0.2, inf},
+			},
+			want: []int{1, 0},
+		},
+		{
+			name: "todos proibidos",
+			cost: [][]float64{
+				{inf, inf},
+				{inf, inf},
+			},
+			want: []int{-1, -1},
+		},
+		{
+			name: "custos negativos (maximização)",
+			cost: [][]float64{
+				{-3, -1},
+				{-2, -2},
+			},
+			want: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Assign(tt.cost); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Assign = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
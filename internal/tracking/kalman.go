@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"image"
+	"math"
+)
+
+// Ruídos relativos à altura da caixa (mesma ideia do DeepSORT): pessoas
+// maiores (mais próximas da câmera) se deslocam mais pixels por segundo
+const (
+	measurementNoiseWeight  = 0.05 // desvio da medição (fração da altura)
+	accelerationNoiseWeight = 1.0  // aceleração não modelada (alturas/s²)
+	initialVelocityWeight   = 1.0  // incerteza inicial da velocidade (alturas/s)
+)
+
+// kalmanFilter é um filtro de Kalman de velocidade constante para a caixa
+// [cx, cy, w, h]. Como o movimento de cada dimensão é independente, o filtro
+// é mantido como quatro filtros 1D (posição, velocidade) com covariância 2x2.
+type kalmanFilter struct {
+	pos [4]float64       // cx, cy, w, h
+	vel [4]float64       // velocidades em pixels/s
+	cov [4][2][2]float64 // covariância (posição, velocidade) de cada dimensão
+}
+
+// newKalmanFilter inicializa o filtro na caixa detectada, com velocidade zero
+func newKalmanFilter(box image.Rectangle) *kalmanFilter {
+	kf := &kalmanFilter{pos: boxToState(box)}
+	h := kf.pos[3]
+	for d := range kf.cov {
+		posStd := 2 * measurementNoiseWeight * h
+		velStd := initialVelocityWeight * h
+		kf.cov[d] = [2][2]float64{{posStd * posStd, 0}, {0, velStd * velStd}}
+	}
+	return kf
+}
+
+// predict avança o estado dt segundos
+func (kf *kalmanFilter) predict(dt float64) {
+	if dt <= 0 {
+		return
+	}
+
+	accel := accelerationNoiseWeight * kf.pos[3]
+	q := accel * accel
+	q00 := q * dt * dt * dt * dt / 4
+	q01 := q * dt * dt * dt / 2
+	q11 := q * dt * dt
+
+	for d := range kf.pos {
+		kf.pos[d] += kf.vel[d] * dt
+
+		p := kf.cov[d]
+		kf.cov[d] = [2][2]float64{
+			{p[0][0] + dt*(p[1][0]+p[0][1]) + dt*dt*p[1][1] + q00, p[0][1] + dt*p[1][1] + q01},
+			{p[1][0] + dt*p[1][1] + q01, p[1][1] + q11},
+		}
+	}
+
+	// Largura/altura nunca ficam negativas
+	kf.pos[2] = math.Max(kf.pos[2], 1)
+	kf.pos[3] = math.Max(kf.pos[3], 1)
+}
+
+// update corrige o estado com a caixa medida
+func (kf *kalmanFilter) update(box image.Rectangle) {
+	z := boxToState(box)
+	noise := measurementNoiseWeight * kf.pos[3]
+	r := noise * noise
+
+	for d := range kf.pos {
+		p := kf.cov[d]
+		s := p[0][0] + r
+		k0 := p[0][0] / s
+		k1 := p[1][0] / s
+
+		residual := z[d] - kf.pos[d]
+		kf.pos[d] += k0 * residual
+		kf.vel[d] += k1 * residual
+
+		kf.cov[d] = [2][2]float64{
+			{(1 - k0) * p[0][0], (1 - k0) * p[0][1]},
+			{p[1][0] - k1*p[0][0], p[1][1] - k1*p[0][1]},
+		}
+	}
+}
+
+// box retorna a caixa estimada
+func (kf *kalmanFilter) box() image.Rectangle {
+	cx, cy, w, h := kf.pos[0], kf.pos[1], kf.pos[2], kf.pos[3]
+	return image.Rect(
+		int(math.Round(cx-w/2)), int(math.Round(cy-h/2)),
+		int(math.Round(cx+w/2)), int(math.Round(cy+h/2)),
+	)
+}
+
+// boxToState converte a caixa para [cx, cy, w, h]
+func boxToState(box image.Rectangle) [4]float64 {
+	w := float64(box.Dx())
+	h := float64(box.Dy())
+	return [4]float64{
+		float64(box.Min.X) + w/2,
+		float64(box.Min.Y) + h/2,
+		math.Max(w, 1),
+		math.Max(h, 1),
+	}
+}
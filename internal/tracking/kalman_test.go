@@ -0,0 +1,73 @@
+package tracking
+
+import (
+	"image"
+	"testing"
+)
+
+func TestKalmanFollowsConstantVelocity(t *testing.T) {
+	// Pessoa andando 200 px/s para a direita e 50 px/s para baixo, a 10 fps
+	const dt = 0.1
+	at := func(frame int) image.Rectangle { return box(20*frame, 5*frame) }
+
+	kf := newKalmanFilter(at(0))
+	for frame := 1; frame <= 20; frame++ {
+		kf.predict(dt)
+		kf.update(at(frame))
+	}
+
+	if vx, vy := kf.vel[0], kf.vel[1]; vx < 190 || vx > 210 || vy < 45 || vy > 55 {
+		t.Errorf("velocidade = (%.1f, %.1f), esperado próximo de (200, 50)", vx, vy)
+	}
+
+	// Sem medição, a previsão continua o movimento
+	kf.predict(3 * dt)
+	want := at(23)
+	if got := kf.box(); absInt(got.Min.X-want.Min.X) > 3 || absInt(got.Min.Y-want.Min.Y) > 3 || got.Size() != want.Size() {
+		t.Errorf("caixa prevista = %v, esperado próximo de %v", got, want)
+	}
+}
+
+func TestKalmanStationary(t *testing.T) {
+	kf := newKalmanFilter(box(100, 100))
+	for i := 0; i < 5; i++ {
+		kf.predict(0.1)
+		kf.update(box(100, 100))
+	}
+
+	kf.predict(2)
+	if got := kf.box(); got != box(100, 100) {
+		t.Errorf("caixa parada = %v, esperado %v", got, box(100, 100))
+	}
+
+	// Intervalo nulo ou negativo não altera o estado
+	before := *kf
+	kf.predict(0)
+	kf.predict(-1)
+	if *kf != before {
+		t.Error("predict com dt <= 0 alterou o filtro")
+	}
+}
+
+func TestKalmanUpdateReducesUncertainty(t *testing.T) {
+	kf := newKalmanFilter(box(0, 0))
+	kf.predict(0.5)
+	before := kf.cov[0][0][0]
+
+	kf.update(box(10, 0))
+	after := kf.cov[0][0][0]
+	if after >= before {
+		t.Errorf("variância da posição após medição = %.2f, esperado menor que %.2f", after, before)
+	}
+	// A estimativa fica entre a previsão e a medição, perto da medição
+	if cx := kf.pos[0]; cx <= 25 || cx > 35 {
+		t.Errorf("centro x = %.2f, esperado entre 25 e 35", cx)
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
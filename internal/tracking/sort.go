@@ -0,0 +1,243 @@
+package tracking
+
+import (
+	"math"
+	"time"
+)
+
+// sortTrack é uma trilha do SORTTracker com seu filtro de Kalman
+type sortTrack struct {
+	Track
+	kf *kalmanFilter
+}
+
+// SORTTracker implementa rastreamento no estilo SORT/ByteTrack: previsão de
+// movimento por filtro de Kalman, associação ótima (algoritmo húngaro) sobre
+// custo de IoU/distância entre centros e estados tentativa/confirmada/perdida.
+// A associação acontece em duas etapas como no ByteTrack: detecções de alta
+// confiança primeiro, depois as de baixa confiança apenas para trilhas que
+// já existiam (oclusões parciais), sem criar trilhas novas.
+type SORTTracker struct {
+	opts       Options
+	tracks     []*sortTrack
+	nextID     int
+	lastUpdate time.Time
+	stats      Stats
+}
+
+// NewSORTTracker cria um tracker SORT
+func NewSORTTracker(opts Options) *SORTTracker {
+	return &SORTTracker{
+		opts:   opts,
+		nextID: 1,
+	}
+}
+
+// Update implementa Tracker
+func (st *SORTTracker) Update(detections []Detection, now time.Time) []Track {
+	// 1. Prevê a posição de todas as trilhas para o instante atual
+	var dt float64
+	if !st.lastUpdate.IsZero() {
+		dt = now.Sub(st.lastUpdate).Seconds()
+	}
+	st.lastUpdate = now
+
+	for _, track := range st.tracks {
+		track.kf.predict(dt)
+		track.Box = track.kf.box()
+		track.Detection = -1
+	}
+
+	// 2. Separa detecções por confiança
+	var high, low []int
+	for i, det := range detections {
+		if det.Confidence >= st.opts.HighConfidence {
+			high = append(high, i)
+		} else {
+			low = append(low, i)
+		}
+	}
+
+	// 3. Primeira etapa: detecções confiáveis contra todas as trilhas
+	unmatchedTracks, unmatchedHigh := st.associate(st.tracks, high, detections, true)
+
+	// 4. Segunda etapa: detecções fracas só mantêm trilhas já confirmadas
+	var confirmed []*sortTrack
+	for _, track := range unmatchedTracks {
+		if track.State != StateTentative {
+			confirmed = append(confirmed, track)
+		}
+	}
+	st.associate(confirmed, low, detections, false)
+
+	// 5. Atualiza estados e remove trilhas expiradas
+	alive := st.tracks[:0]
+	for _, track := range st.tracks {
+		if track.Detection == -1 {
+			// Trilhas tentativas não sobrevivem a um frame sem detecção
+			if track.State == StateTentative || now.Sub(track.LastSeen) > st.opts.MaxAge {
+				continue
+			}
+			track.State = StateLost
+		}
+		alive = append(alive, track)
+	}
+	st.tracks = alive
+
+	// 6. Detecções confiáveis sem trilha iniciam trilhas novas
+	for _, i := range unmatchedHigh {
+		det := detections[i]
+		if !st.makeRoom(det.Confidence) {
+			continue
+		}
+
+		track := &sortTrack{
+			Track: Track{
+				ID:         st.nextID,
+				Box:        det.Box,
+				State:      StateTentative,
+				Confidence: det.Confidence,
+				Hits:       1,
+				FirstSeen:  now,
+				LastSeen:   now,
+				Detection:  i,
+			},
+			kf: newKalmanFilter(det.Box),
+		}
+		if st.opts.MinHits <= 1 {
+			track.State = StateConfirmed
+		}
+		st.nextID++
+		st.stats.Created++
+		st.tracks = append(st.tracks, track)
+	}
+	st.stats.Active = len(st.tracks)
+
+	result := make([]Track, len(st.tracks))
+	for i, track := range st.tracks {
+		result[i] = track.Track
+	}
+	return result
+}
+
+// Predict implementa Tracker: move as caixas pelo filtro de Kalman
+func (st *SORTTracker) Predict(now time.Time) []Track {
+	var dt float64
+	if !st.lastUpdate.IsZero() {
+		dt = now.Sub(st.lastUpdate).Seconds()
+	}
+	st.lastUpdate = now
+
+	result := make([]Track, len(st.tracks))
+	for i, track := range st.tracks {
+		track.kf.predict(dt)
+		track.Box = track.kf.box()
+		track.Detection = -1
+		result[i] = track.Track
+	}
+	return result
+}
+
+// Stats implementa Tracker
+func (st *SORTTracker) Stats() Stats {
+	return st.stats
+}
+
+// makeRoom remove uma trilha se o limite foi atingido.
+// Retorna false se a detecção nova não deve criar trilha.
+func (st *SORTTracker) makeRoom(confidence float32) bool {
+	if st.opts.MaxTracks <= 0 || len(st.tracks) < st.opts.MaxTracks {
+		return true
+	}
+
+	// Trilhas já associadas neste frame (inclusive as recém-criadas) não são
+	// candidatas; positions guarda o índice de cada candidata em st.tracks
+	var tracks []*Track
+	var positions []int
+	for i, track := range st.tracks {
+		if track.Detection == -1 {
+			tracks = append(tracks, &track.Track)
+			positions = append(positions, i)
+		}
+	}
+	victim := evictionCandidate(tracks, st.opts.EvictionPolicy, confidence)
+	if victim == -1 {
+		return false
+	}
+
+	index := positions[victim]
+	st.tracks = append(st.tracks[:index], st.tracks[index+1:]...)
+	st.stats.Evictions++
+	return true
+}
+
+// associate atribui detecções a trilhas e atualiza as trilhas associadas.
+// Retorna as trilhas e os índices de detecção que ficaram sem par.
+func (st *SORTTracker) associate(tracks []*sortTrack, detIdx []int, detections []Detection, useDistance bool) ([]*sortTrack, []int) {
+	if len(tracks) == 0 || len(detIdx) == 0 {
+		return tracks, detIdx
+	}
+
+	cost := make([][]float64, len(tracks))
+	for t, track := range tracks {
+		cost[t] = make([]float64, len(detIdx))
+		for d, i := range detIdx {
+			cost[t][d] = st.matchCost(track, detections[i], useDistance)
+		}
+	}
+
+	assignment := Assign(cost)
+	matchedDet := make([]bool, len(detIdx))
+	var unmatchedTracks []*sortTrack
+
+	for t, d := range assignment {
+		if d == -1 {
+			unmatchedTracks = append(unmatchedTracks, tracks[t])
+			continue
+		}
+		matchedDet[d] = true
+		st.updateTrack(tracks[t], detections[detIdx[d]], detIdx[d])
+	}
+
+	var unmatchedDet []int
+	for d, matched := range matchedDet {
+		if !matched {
+			unmatchedDet = append(unmatchedDet, detIdx[d])
+		}
+	}
+	return unmatchedTracks, unmatchedDet
+}
+
+// matchCost calcula o custo de associar trilha e detecção.
+// Pares com sobreposição custam 1-IoU; sem sobreposição suficiente, a
+// distância entre centros (normalizada) é usada como fallback, sempre mais
+// cara que qualquer par com IoU válido. Pares fora dos limites são proibidos.
+func (st *SORTTracker) matchCost(track *sortTrack, det Detection, useDistance bool) float64 {
+	iou := IoU(track.Box, det.Box)
+	if iou >= st.opts.IoUThreshold && iou > 0 {
+		return 1 - iou
+	}
+
+	if useDistance && st.opts.ProximityThreshold > 0 {
+		distance := centerDistance(track.Box, det.Box)
+		if distance < st.opts.ProximityThreshold {
+			return 1 + distance/st.opts.ProximityThreshold
+		}
+	}
+
+	return math.Inf(1)
+}
+
+// updateTrack corrige a trilha com a detecção associada
+func (st *SORTTracker) updateTrack(track *sortTrack, det Detection, index int) {
+	track.kf.update(det.Box)
+	track.Box = det.Box
+	track.Confidence = det.Confidence
+	track.Hits++
+	track.LastSeen = st.lastUpdate
+	track.Detection = index
+
+	if track.State == StateLost || track.Hits >= st.opts.MinHits {
+		track.State = StateConfirmed
+	}
+}
@@ -0,0 +1,160 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+)
+
+// stepper alimenta o tracker com frames a 10 fps
+type stepper struct {
+	tracker Tracker
+	now     time.Time
+}
+
+func (s *stepper) step(detections ...Detection) []Track {
+	tracks := s.tracker.Update(detections, s.now)
+	s.now = s.now.Add(100 * time.Millisecond)
+	return tracks
+}
+
+// trackByID retorna a trilha com o ID, se viva
+func trackByID(tracks []Track, id int) (Track, bool) {
+	for _, track := range tracks {
+		if track.ID == id {
+			return track, true
+		}
+	}
+	return Track{}, false
+}
+
+func TestSORTLifecycle(t *testing.T) {
+	opts := testOptions(0, EvictOldest)
+	opts.MinHits = 3
+	opts.MaxAge = 300 * time.Millisecond
+	s := &stepper{tracker: NewSORTTracker(opts), now: trackingStart}
+	person := Detection{Box: box(100, 100), Confidence: 0.9}
+
+	wantStates := []struct {
+		detections []Detection
+		state      TrackState
+		alive      bool
+	}{
+		{[]Detection{person}, StateTentative, true},
+		{[]Detection{person}, StateTentative, true},
+		{[]Detection{person}, StateConfirmed, true},
+		{nil, StateLost, true},                      // 100 ms sem detecção
+		{nil, StateLost, true},                      // 200 ms
+		{nil, StateLost, true},                      // 300 ms: ainda dentro de MaxAge
+		{[]Detection{person}, StateConfirmed, true}, // reencontrada com o mesmo ID
+		{nil, StateLost, true},
+		{nil, StateLost, true},
+		{nil, StateLost, true},
+		{nil, 0, false}, // 400 ms: expirada
+	}
+
+	for i, want := range wantStates {
+		tracks := s.step(want.detections...)
+		track, ok := trackByID(tracks, 1)
+		if ok != want.alive {
+			t.Fatalf("frame %d: trilha viva = %v, esperado %v", i, ok, want.alive)
+		}
+		if ok && track.State != want.state {
+			t.Fatalf("frame %d: estado = %v, esperado %v", i, track.State, want.state)
+		}
+		if len(tracks) > 1 {
+			t.Fatalf("frame %d: %d trilhas, esperado no máximo 1", i, len(tracks))
+		}
+	}
+}
+
+func TestSORTTentativeDiesOnMiss(t *testing.T) {
+	opts := testOptions(0, EvictOldest)
+	opts.MinHits = 3
+	s := &stepper{tracker: NewSORTTracker(opts), now: trackingStart}
+
+	s.step(Detection{Box: box(100, 100), Confidence: 0.9})
+	if tracks := s.step(); len(tracks) != 0 {
+		t.Errorf("trilhas = %d, esperado tentativa removida no primeiro frame sem detecção", len(tracks))
+	}
+}
+
+func TestSORTLowConfidenceSecondPass(t *testing.T) {
+	tests := []struct {
+		name      string
+		minHits   int
+		warmup    int // frames com detecção confiável antes da fraca
+		wantAlive bool
+		wantState TrackState
+	}{
+		{name: "mantém trilha confirmada", minHits: 1, warmup: 3, wantAlive: true, wantState: StateConfirmed},
+		{name: "não mantém trilha tentativa", minHits: 3, warmup: 1, wantAlive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions(0, EvictOldest)
+			opts.MinHits = tt.minHits
+			s := &stepper{tracker: NewSORTTracker(opts), now: trackingStart}
+
+			for i := 0; i < tt.warmup; i++ {
+				s.step(Detection{Box: box(100, 100), Confidence: 0.9})
+			}
+
+			// Oclusão parcial: mesma pessoa com confiança abaixo de HighConfidence
+			tracks := s.step(Detection{Box: box(102, 100), Confidence: 0.2})
+			track, ok := trackByID(tracks, 1)
+			if ok != tt.wantAlive {
+				t.Fatalf("trilha viva = %v, esperado %v", ok, tt.wantAlive)
+			}
+			if ok && (track.State != tt.wantState || track.Detection != 0 || track.Confidence != 0.2) {
+				t.Errorf("trilha = %+v, esperado %v associada à detecção fraca", track, tt.wantState)
+			}
+			if len(tracks) > 1 {
+				t.Errorf("trilhas = %d: detecção fraca não deve criar trilha", len(tracks))
+			}
+		})
+	}
+}
+
+func TestSORTLowConfidenceNeedsOverlap(t *testing.T) {
+	s := &stepper{tracker: NewSORTTracker(testOptions(0, EvictOldest)), now: trackingStart}
+	s.step(Detection{Box: box(100, 100), Confidence: 0.9})
+
+	// Perto o bastante para a distância entre centros, mas sem IoU: a segunda
+	// etapa só usa sobreposição
+	tracks := s.step(Detection{Box: box(140, 100), Confidence: 0.2})
+	if len(tracks) != 1 || tracks[0].State != StateLost {
+		t.Errorf("trilhas = %+v, esperado a trilha 1 perdida", tracks)
+	}
+}
+
+func TestSORTCrossingKeepsIDs(t *testing.T) {
+	// Duas pessoas cruzam em sentidos opostos a 300 px/s, com as caixas se
+	// sobrepondo durante alguns frames. Sem a previsão de movimento, a caixa
+	// parada de cada trilha casaria com a outra pessoa após o cruzamento.
+	s := &stepper{tracker: NewSORTTracker(testOptions(0, EvictOldest)), now: trackingStart}
+
+	ids := map[int]int{} // índice da detecção → ID da trilha
+	for frame := 0; frame <= 13; frame++ {
+		left := Detection{Box: box(30*frame, 100), Confidence: 0.9}
+		right := Detection{Box: box(400-30*frame, 110), Confidence: 0.9}
+
+		tracks := s.step(left, right)
+		if len(tracks) != 2 {
+			t.Fatalf("frame %d: %d trilhas, esperado 2", frame, len(tracks))
+		}
+		for _, track := range tracks {
+			if track.Detection == -1 {
+				t.Fatalf("frame %d: trilha %d sem detecção", frame, track.ID)
+			}
+			if id, ok := ids[track.Detection]; !ok {
+				ids[track.Detection] = track.ID
+			} else if id != track.ID {
+				t.Fatalf("frame %d: pessoa %d trocou de ID %d para %d", frame, track.Detection, id, track.ID)
+			}
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("as duas pessoas ficaram com o ID %d", ids[0])
+	}
+}
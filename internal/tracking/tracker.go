@@ -0,0 +1,172 @@
+package tracking
+
+import (
+	"fmt"
+	"image"
+	"time"
+)
+
+// Tipos de tracker disponíveis
+const (
+	TypeSORT     = "sort"
+	TypeCentroid = "centroid"
+)
+
+// Políticas de remoção quando o limite de trilhas é atingido
+const (
+	EvictOldest           = "oldest"            // trilha atualizada há mais tempo
+	EvictLowestConfidence = "lowest_confidence" // trilha com menor confiança
+)
+
+// TrackState representa o estado de uma trilha
+type TrackState int
+
+const (
+	// StateTentative trilha recém-criada, aguardando confirmação
+	StateTentative TrackState = iota
+	// StateConfirmed trilha confirmada e associada a uma detecção neste frame
+	StateConfirmed
+	// StateLost trilha confirmada sem detecção neste frame (posição prevista)
+	StateLost
+)
+
+// String retorna o nome do estado
+func (s TrackState) String() string {
+	switch s {
+	case StateTentative:
+		return "tentative"
+	case StateConfirmed:
+		return "confirmed"
+	case StateLost:
+		return "lost"
+	default:
+		return fmt.Sprintf("TrackState(%d)", int(s))
+	}
+}
+
+// Detection é a entrada do tracker: uma caixa detectada no frame
+type Detection struct {
+	Box        image.Rectangle
+	Confidence float32
+}
+
+// Track é uma trilha mantida pelo tracker
+type Track struct {
+	ID         int
+	Box        image.Rectangle // caixa corrigida pela detecção ou prevista, se perdida
+	State      TrackState
+	Confidence float32 // confiança da última detecção associada
+	Hits       int     // quantidade de frames com detecção associada
+	FirstSeen  time.Time
+	LastSeen   time.Time // último frame com detecção associada
+	Detection  int       // índice da detecção associada neste update, -1 se nenhuma
+}
+
+// Center retorna o centro da caixa da trilha
+func (t Track) Center() image.Point {
+	return image.Pt(t.Box.Min.X+t.Box.Dx()/2, t.Box.Min.Y+t.Box.Dy()/2)
+}
+
+// Tracker associa detecções entre frames mantendo IDs estáveis
+type Tracker interface {
+	// Update processa as detecções do frame no instante now e retorna as trilhas vivas
+	Update(detections []Detection, now time.Time) []Track
+	// Predict avança as trilhas até now sem detecções (frames em que a
+	// detecção foi pulada), sem mudar estados, e retorna as trilhas vivas
+	Predict(now time.Time) []Track
+	// Stats retorna contadores do tracker
+	Stats() Stats
+}
+
+// Stats contém contadores acumulados do tracker
+type Stats struct {
+	Active    int // trilhas vivas no momento
+	Created   int // trilhas criadas desde o início
+	Evictions int // trilhas removidas por falta de capacidade
+}
+
+// Options parametriza os trackers
+type Options struct {
+	MaxAge             time.Duration // tempo sem detecção até descartar a trilha
+	ProximityThreshold float64       // distância máxima (pixels) entre centros para associar
+	IoUThreshold       float64       // IoU mínimo para associar por sobreposição
+	MinHits            int           // detecções consecutivas para confirmar a trilha
+	HighConfidence     float32       // detecções abaixo disso só mantêm trilhas existentes
+	MaxTracks          int           // limite de trilhas simultâneas (0 = sem limite)
+	EvictionPolicy     string        // oldest ou lowest_confidence
+}
+
+// New cria o tracker do tipo informado
+func New(trackerType string, opts Options) (Tracker, error) {
+	switch opts.EvictionPolicy {
+	case "":
+		opts.EvictionPolicy = EvictOldest
+	case EvictOldest, EvictLowestConfidence:
+	default:
+		return nil, fmt.Errorf("política de remoção desconhecida: %q", opts.EvictionPolicy)
+	}
+
+	switch trackerType {
+	case TypeSORT, "":
+		return NewSORTTracker(opts), nil
+	case TypeCentroid:
+		return NewCentroidTracker(opts), nil
+	default:
+		return nil, fmt.Errorf("tipo de tracker desconhecido: %q", trackerType)
+	}
+}
+
+// evictionCandidate escolhe a trilha a remover para abrir espaço para uma
+// detecção nova com a confiança informada. Trilhas perdidas ou tentativas
+// são removidas antes das confirmadas. Retorna -1 quando a detecção nova
+// não deve substituir nenhuma trilha (política lowest_confidence com
+// detecção menos confiável que todas as trilhas).
+func evictionCandidate(tracks []*Track, policy string, confidence float32) int {
+	candidate := -1
+	for i, track := range tracks {
+		if candidate == -1 || evictsBefore(track, tracks[candidate], policy) {
+			candidate = i
+		}
+	}
+
+	if candidate != -1 && policy == EvictLowestConfidence &&
+		tracks[candidate].State == StateConfirmed && tracks[candidate].Confidence >= confidence {
+		return -1
+	}
+	return candidate
+}
+
+// evictsBefore indica se a trilha a deve ser removida antes da trilha b
+func evictsBefore(a, b *Track, policy string) bool {
+	aConfirmed := a.State == StateConfirmed
+	bConfirmed := b.State == StateConfirmed
+	if aConfirmed != bConfirmed {
+		return !aConfirmed
+	}
+
+	if policy == EvictLowestConfidence && a.Confidence != b.Confidence {
+		return a.Confidence < b.Confidence
+	}
+	return a.LastSeen.Before(b.LastSeen)
+}
+
+// IoU calcula a interseção sobre união de duas caixas
+func IoU(a, b image.Rectangle) float64 {
+	inter := a.Intersect(b)
+	if inter.Empty() {
+		return 0
+	}
+	interArea := float64(inter.Dx() * inter.Dy())
+	union := float64(a.Dx()*a.Dy()+b.Dx()*b.Dy()) - interArea
+	if union <= 0 {
+		return 0
+	}
+	return interArea / union
+}
+
+// centerDistance calcula a distância euclidiana entre os centros das caixas
+func centerDistance(a, b image.Rectangle) float64 {
+	ac := image.Pt(a.Min.X+a.Dx()/2, a.Min.Y+a.Dy()/2)
+	bc := image.Pt(b.Min.X+b.Dx()/2, b.Min.Y+b.Dy()/2)
+	return pointDistance(ac, bc)
+}
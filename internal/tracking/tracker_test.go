@@ -0,0 +1,182 @@
+package tracking
+
+import (
+	"image"
+	"sort"
+	"testing"
+	"time"
+)
+
+// trackingStart é o instante do primeiro frame nos testes
+var trackingStart = time.Date(2025, 1, 10, 14, 0, 0, 0, time.UTC)
+
+// box cria a caixa 50x100 de uma pessoa com canto superior esquerdo em (x, y)
+func box(x, y int) image.Rectangle {
+	return image.Rect(x, y, x+50, y+100)
+}
+
+// testOptions são as opções dos trackers nos testes (confirmação imediata)
+func testOptions(maxTracks int, policy string) Options {
+	return Options{
+		MaxAge:             5 * time.Second,
+		ProximityThreshold: 50,
+		IoUThreshold:       0.3,
+		MinHits:            1,
+		HighConfidence:     0.4,
+		MaxTracks:          maxTracks,
+		EvictionPolicy:     policy,
+	}
+}
+
+// trackIDs retorna os IDs das trilhas em ordem crescente
+func trackIDs(tracks []Track) []int {
+	ids := make([]int, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEvictionAtCapacity(t *testing.T) {
+	a := Detection{Box: box(0, 0), Confidence: 0.9}
+	b := Detection{Box: box(200, 0), Confidence: 0.6}
+	c := Detection{Box: box(400, 0), Confidence: 0.95}
+
+	for _, trackerType := range []string{TypeSORT, TypeCentroid} {
+		for _, policy := range []string{EvictOldest, EvictLowestConfidence} {
+			t.Run(trackerType+"/"+policy, func(t *testing.T) {
+				tracker, err := New(trackerType, testOptions(2, policy))
+				if err != nil {
+					t.Fatal(err)
+				}
+				now := trackingStart
+				step := func(detections ...Detection) []Track {
+					tracks := tracker.Update(detections, now)
+					now = now.Add(100 * time.Millisecond)
+					return tracks
+				}
+
+				initial := trackIDs(step(a, b))
+				if !equalIDs(initial, []int{1, 2}) {
+					t.Fatalf("trilhas iniciais = %v, esperado [1 2]", initial)
+				}
+
+				// Limite atingido com as duas pessoas visíveis: a terceira não
+				// entra e ninguém é removido (sem troca de IDs a cada frame)
+				for i := 0; i < 5; i++ {
+					if got := trackIDs(step(a, b, c)); !equalIDs(got, initial) {
+						t.Fatalf("frame %d: trilhas = %v, esperado %v", i, got, initial)
+					}
+				}
+				if got := tracker.Stats().Evictions; got != 0 {
+					t.Fatalf("remoções com todas as trilhas associadas = %d, esperado 0", got)
+				}
+
+				// B some: sua trilha dá lugar à detecção nova
+				if got := trackIDs(step(a, c)); !equalIDs(got, []int{1, 3}) {
+					t.Errorf("trilhas após B sumir = %v, esperado [1 3]", got)
+				}
+				if got := tracker.Stats().Evictions; got != 1 {
+					t.Errorf("remoções = %d, esperado 1", got)
+				}
+			})
+		}
+	}
+}
+
+func TestEvictionCandidate(t *testing.T) {
+	at := func(ms int) time.Time { return trackingStart.Add(time.Duration(ms) * time.Millisecond) }
+	tracks := func(specs ...Track) []*Track {
+		result := make([]*Track, len(specs))
+		for i := range specs {
+			result[i] = &specs[i]
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		tracks     []*Track
+		confidence float32
+		want       int
+	}{
+		{
+			name:   "oldest: atualizada há mais tempo",
+			policy: EvictOldest,
+			tracks: tracks(
+				Track{ID: 1, State: StateConfirmed, Confidence: 0.5, LastSeen: at(200)},
+				Track{ID: 2, State: StateConfirmed, Confidence: 0.9, LastSeen: at(100)},
+			),
+			confidence: 0.6,
+			want:       1,
+		},
+		{
+			name:   "oldest: perdida antes de confirmada",
+			policy: EvictOldest,
+			tracks: tracks(
+				Track{ID: 1, State: StateConfirmed, Confidence: 0.9, LastSeen: at(0)},
+				Track{ID: 2, State: StateLost, Confidence: 0.9, LastSeen: at(100)},
+			),
+			confidence: 0.6,
+			want:       1,
+		},
+		{
+			name:   "lowest_confidence: menor confiança",
+			policy: EvictLowestConfidence,
+			tracks: tracks(
+				Track{ID: 1, State: StateConfirmed, Confidence: 0.5, LastSeen: at(200)},
+				Track{ID: 2, State: StateConfirmed, Confidence: 0.9, LastSeen: at(100)},
+			),
+			confidence: 0.6,
+			want:       0,
+		},
+		{
+			name:   "lowest_confidence: detecção mais fraca que todas as confirmadas",
+			policy: EvictLowestConfidence,
+			tracks: tracks(
+				Track{ID: 1, State: StateConfirmed, Confidence: 0.5, LastSeen: at(200)},
+				Track{ID: 2, State: StateConfirmed, Confidence: 0.9, LastSeen: at(100)},
+			),
+			confidence: 0.45,
+			want:       -1,
+		},
+		{
+			name:   "lowest_confidence: perdida sai mesmo com confiança alta",
+			policy: EvictLowestConfidence,
+			tracks: tracks(
+				Track{ID: 1, State: StateConfirmed, Confidence: 0.5, LastSeen: at(200)},
+				Track{ID: 2, State: StateLost, Confidence: 0.9, LastSeen: at(100)},
+			),
+			confidence: 0.45,
+			want:       1,
+		},
+		{
+			name:       "sem candidatas",
+			policy:     EvictOldest,
+			confidence: 0.9,
+			want:       -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evictionCandidate(tt.tracks, tt.policy, tt.confidence); got != tt.want {
+				t.Errorf("candidata = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
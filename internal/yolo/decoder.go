@@ -0,0 +1,264 @@
+// Package yolo decodifica a saída bruta de modelos YOLO em detecções, sem
+// depender do OpenCV: recebe o tensor como []float32 e sua forma.
+package yolo
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// Layouts de saída suportados
+const (
+	// LayoutTransposed é [4+C, N]: atributos nas linhas (YOLOv8/YOLO11)
+	LayoutTransposed = "transposed"
+	// LayoutRowMajor é [N, 4+C]: um candidato por linha
+	LayoutRowMajor = "row_major"
+	// LayoutObjectness é [N, 5+C]: cx, cy, w, h, objectness, classes (YOLOv5)
+	LayoutObjectness = "objectness"
+	// LayoutEndToEnd é [N, 6]: x1, y1, x2, y2, score, classe, já sem NMS (YOLOv10)
+	LayoutEndToEnd = "end_to_end"
+)
+
+// Detection é uma detecção decodificada, em pixels do frame
+type Detection struct {
+	ClassID    int             `json:"class_id"`
+	Confidence float32         `json:"confidence"`
+	Box        image.Rectangle `json:"box"`
+}
+
+// Options configura o decodificador
+type Options struct {
+	Layout              string
+	NumClasses          int
+	NumCandidates       int // candidatos esperados na saída (0 = deduzido da forma)
+	InputWidth          int // dimensões da entrada da rede, em pixels
+	InputHeight         int
+	ConfidenceThreshold float32
+	MaxClassID          int // maior ID aceito, antes do remapeamento (0 = só a classe 0)
+	MinObjectSize       int // largura/altura mínima da caixa em pixels do frame
+
+	// Regras por classe (nil = valores globais acima para todas as classes)
+	Remap           []int     // ID do modelo → ID final (junta classes)
+	ClassEnabled    []bool    // classes mantidas, por ID final
+	ClassThresholds []float32 // confiança mínima por ID final
+	ClassMinSizes   []int     // tamanho mínimo por ID final
+}
+
+// Decoder converte tensores de saída em detecções
+type Decoder struct {
+	opts Options
+}
+
+// NewDecoder valida as opções e cria o decodificador
+func NewDecoder(opts Options) (*Decoder, error) {
+	switch opts.Layout {
+	case LayoutTransposed, LayoutRowMajor, LayoutObjectness, LayoutEndToEnd:
+	default:
+		return nil, fmt.Errorf("layout de saída desconhecido: %q", opts.Layout)
+	}
+	if opts.NumClasses <= 0 {
+		return nil, fmt.Errorf("número de classes deve ser positivo (atual: %d)", opts.NumClasses)
+	}
+	if opts.InputWidth <= 0 || opts.InputHeight <= 0 {
+		return nil, fmt.Errorf("dimensões de entrada inválidas: %dx%d", opts.InputWidth, opts.InputHeight)
+	}
+	if opts.MaxClassID < 0 {
+		return nil, fmt.Errorf("maior ID de classe não pode ser negativo (atual: %d)", opts.MaxClassID)
+	}
+	for _, rule := range []struct {
+		name string
+		n    int
+	}{
+		{"Remap", len(opts.Remap)},
+		{"ClassEnabled", len(opts.ClassEnabled)},
+		{"ClassThresholds", len(opts.ClassThresholds)},
+		{"ClassMinSizes", len(opts.ClassMinSizes)},
+	} {
+		if rule.n != 0 && rule.n != opts.NumClasses {
+			return nil, fmt.Errorf("%s tem %d entradas, esperado %d (uma por classe)", rule.name, rule.n, opts.NumClasses)
+		}
+	}
+	for _, id := range opts.Remap {
+		if id < 0 || id >= opts.NumClasses {
+			return nil, fmt.Errorf("remapeamento para classe inválida: %d", id)
+		}
+	}
+	return &Decoder{opts: opts}, nil
+}
+
+// Attributes retorna quantos valores cada candidato tem no layout
+func Attributes(layout string, numClasses int) int {
+	switch layout {
+	case LayoutObjectness:
+		return 5 + numClasses
+	case LayoutEndToEnd:
+		return 6
+	default:
+		return 4 + numClasses
+	}
+}
+
+// NMSFree indica se o layout já vem sem caixas duplicadas
+func (d *Decoder) NMSFree() bool {
+	return d.opts.Layout == LayoutEndToEnd
+}
+
+// tensor dá acesso ao atributo a do candidato i, independente do layout
+type tensor struct {
+	data       []float32
+	candidates int
+	attributes int
+	transposed bool
+}
+
+func (t tensor) at(i, a int) float32 {
+	if t.transposed {
+		return t.data[a*t.candidates+i]
+	}
+	return t.data[i*t.attributes+a]
+}
+
+// Decode converte a saída de uma imagem (forma [A, N] ou [1, A, N] no layout
+// transposto; [N, A] ou [1, N, A] nos demais) em detecções no frame. tr é a
+// transformação usada no pré-processamento (Stretch ou Letterbox).
+func (d *Decoder) Decode(data []float32, shape []int, tr Transform) ([]Detection, error) {
+	t, err := d.tensor(data, shape)
+	if err != nil {
+		return nil, err
+	}
+
+	var detections []Detection
+	for i := 0; i < t.candidates; i++ {
+		var det Detection
+		if d.opts.Layout == LayoutEndToEnd {
+			det.ClassID = int(math.Round(float64(t.at(i, 5))))
+			det.Confidence = t.at(i, 4)
+		} else {
+			det.ClassID, det.Confidence = d.bestClass(t, i)
+		}
+
+		// Valida detecção
+		if det.ClassID < 0 || det.ClassID >= d.opts.NumClasses {
+			continue
+		}
+		if det.ClassID > d.opts.MaxClassID {
+			continue
+		}
+		if d.opts.Remap != nil {
+			det.ClassID = d.opts.Remap[det.ClassID]
+		}
+		if d.opts.ClassEnabled != nil && !d.opts.ClassEnabled[det.ClassID] {
+			continue
+		}
+		if det.Confidence < d.threshold(det.ClassID) {
+			continue
+		}
+
+		// Converte coordenadas para pixels do frame
+		if d.opts.Layout == LayoutEndToEnd {
+			x1, y1, x2, y2 := t.at(i, 0), t.at(i, 1), t.at(i, 2), t.at(i, 3)
+			det.Box = toPixels((x1+x2)/2, (y1+y2)/2, x2-x1, y2-y1, tr)
+		} else {
+			det.Box = toPixels(t.at(i, 0), t.at(i, 1), t.at(i, 2), t.at(i, 3), tr)
+		}
+
+		// Filtra objetos muito pequenos
+		if minSize := d.minSize(det.ClassID); det.Box.Dx() < minSize || det.Box.Dy() < minSize {
+			continue
+		}
+		detections = append(detections, det)
+	}
+
+	return detections, nil
+}
+
+// threshold retorna a confiança mínima da classe
+func (d *Decoder) threshold(classID int) float32 {
+	if d.opts.ClassThresholds != nil {
+		return d.opts.ClassThresholds[classID]
+	}
+	return d.opts.ConfidenceThreshold
+}
+
+// minSize retorna o tamanho mínimo da caixa da classe
+func (d *Decoder) minSize(classID int) int {
+	if d.opts.ClassMinSizes != nil {
+		return d.opts.ClassMinSizes[classID]
+	}
+	return d.opts.MinObjectSize
+}
+
+// tensor valida a forma da saída contra o layout e as opções
+func (d *Decoder) tensor(data []float32, shape []int) (tensor, error) {
+	dims := shape
+	if len(dims) == 3 {
+		if dims[0] != 1 {
+			return tensor{}, fmt.Errorf("saída com lote %d: decodifique uma imagem por vez", dims[0])
+		}
+		dims = dims[1:]
+	}
+	if len(dims) != 2 {
+		return tensor{}, fmt.Errorf("forma de saída inesperada %v (esperado 2 ou 3 dimensões)", shape)
+	}
+
+	t := tensor{data: data, transposed: d.opts.Layout == LayoutTransposed}
+	if t.transposed {
+		t.attributes, t.candidates = dims[0], dims[1]
+	} else {
+		t.candidates, t.attributes = dims[0], dims[1]
+	}
+
+	if expected := Attributes(d.opts.Layout, d.opts.NumClasses); t.attributes != expected {
+		return tensor{}, fmt.Errorf("saída %v tem %d atributos por candidato, esperado %d no layout %s",
+			shape, t.attributes, expected, d.opts.Layout)
+	}
+	if d.opts.NumCandidates > 0 && t.candidates != d.opts.NumCandidates {
+		return tensor{}, fmt.Errorf("saída %v tem %d candidatos, esperado %d", shape, t.candidates, d.opts.NumCandidates)
+	}
+	if len(data) < t.candidates*t.attributes {
+		return tensor{}, fmt.Errorf("saída com %d valores, forma %v exige %d", len(data), shape, t.candidates*t.attributes)
+	}
+	return t, nil
+}
+
+// bestClass encontra a classe com maior confiança. No layout com objectness,
+// a confiança é objectness × score da classe.
+func (d *Decoder) bestClass(t tensor, i int) (int, float32) {
+	first := 4
+	if d.opts.Layout == LayoutObjectness {
+		first = 5
+	}
+
+	bestClassID := -1
+	var maxScore float32
+	for c := 0; c < d.opts.NumClasses; c++ {
+		if score := t.at(i, first+c); score > maxScore {
+			maxScore = score
+			bestClassID = c
+		}
+	}
+
+	if d.opts.Layout == LayoutObjectness {
+		maxScore *= t.at(i, 4)
+	}
+	return bestClassID, maxScore
+}
+
+// toPixels converte centro e dimensões na entrada da rede para uma caixa
+// no frame (transformação inversa do pré-processamento). As quatro bordas
+// são calculadas a partir do centro e cortadas no frame uma a uma, para que
+// caixas na borda sejam recortadas e não deslocadas para dentro.
+func toPixels(centerX, centerY, width, height float32, tr Transform) image.Rectangle {
+	left := int((centerX - width/2 - float32(tr.PadX)) * tr.ScaleX)
+	top := int((centerY - height/2 - float32(tr.PadY)) * tr.ScaleY)
+	right := int((centerX + width/2 - float32(tr.PadX)) * tr.ScaleX)
+	bottom := int((centerY + height/2 - float32(tr.PadY)) * tr.ScaleY)
+
+	return image.Rect(
+		min(max(0, left), tr.FrameWidth),
+		min(max(0, top), tr.FrameHeight),
+		min(max(0, right), tr.FrameWidth),
+		min(max(0, bottom), tr.FrameHeight),
+	)
+}
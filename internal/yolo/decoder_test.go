@@ -0,0 +1,319 @@
+package yolo
+
+import (
+	"encoding/json"
+	"flag"
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regrava os arquivos golden em testdata")
+
+// candidate é um candidato de teste em coordenadas da entrada da rede
+type candidate struct {
+	cx, cy, w, h float32
+	objectness   float32
+	scores       []float32
+}
+
+// Candidatos comuns a todos os layouts (3 classes, entrada 640x640)
+var candidates = []candidate{
+	{cx: 320, cy: 320, w: 100, h: 200, objectness: 0.9, scores: []float32{0.1, 0.9, 0.2}}, // classe 1
+	{cx: 100, cy: 100, w: 80, h: 80, objectness: 0.9, scores: []float32{0.1, 0.1, 0.2}},   // confiança baixa
+	{cx: 200, cy: 400, w: 5, h: 50, objectness: 0.9, scores: []float32{0.8, 0.1, 0.1}},    // pequena demais
+	{cx: 630, cy: 10, w: 40, h: 40, objectness: 0.5, scores: []float32{0.6, 0.0, 0.0}},    // cortada na borda
+}
+
+const numClasses = 3
+
+// encode monta o tensor de saída de um layout a partir dos candidatos
+func encode(layout string) ([]float32, []int) {
+	attributes := Attributes(layout, numClasses)
+	rows := make([][]float32, len(candidates))
+	for i, c := range candidates {
+		switch layout {
+		case LayoutObjectness:
+			rows[i] = append([]float32{c.cx, c.cy, c.w, c.h, c.objectness}, c.scores...)
+		case LayoutEndToEnd:
+			best := 0
+			for k, s := range c.scores {
+				if s > c.scores[best] {
+					best = k
+				}
+			}
+			rows[i] = []float32{c.cx - c.w/2, c.cy - c.h/2, c.cx + c.w/2, c.cy + c.h/2, c.scores[best], float32(best)}
+		default:
+			rows[i] = append([]float32{c.cx, c.cy, c.w, c.h}, c.scores...)
+		}
+	}
+
+	data := make([]float32, 0, len(candidates)*attributes)
+	if layout == LayoutTransposed {
+		for a := 0; a < attributes; a++ {
+			for i := range rows {
+				data = append(data, rows[i][a])
+			}
+		}
+		return data, []int{1, attributes, len(candidates)}
+	}
+	for _, row := range rows {
+		data = append(data, row...)
+	}
+	return data, []int{1, len(candidates), attributes}
+}
+
+func TestDecodeGolden(t *testing.T) {
+	// Frame 16:9 em entrada 640x640
+	stretch := Stretch(1280, 720, 640, 640)
+	letterbox := NewLetterbox(1280, 720, 640, 640).Transform
+
+	tests := []struct {
+		name      string
+		layout    string
+		transform Transform
+	}{
+		{name: "transposed", layout: LayoutTransposed, transform: stretch},
+		{name: "row_major", layout: LayoutRowMajor, transform: stretch},
+		{name: "objectness", layout: LayoutObjectness, transform: stretch},
+		{name: "end_to_end", layout: LayoutEndToEnd, transform: stretch},
+		// No letterbox, o candidato cortado na borda fica inteiro na faixa de
+		// preenchimento e é descartado
+		{name: "transposed_letterbox", layout: LayoutTransposed, transform: letterbox},
+		{name: "end_to_end_letterbox", layout: LayoutEndToEnd, transform: letterbox},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewDecoder(Options{
+				Layout:              tt.layout,
+				NumClasses:          numClasses,
+				InputWidth:          640,
+				InputHeight:         640,
+				ConfidenceThreshold: 0.25,
+				MaxClassID:          numClasses - 1,
+				MinObjectSize:       20,
+			})
+			if err != nil {
+				t.Fatalf("NewDecoder: %v", err)
+			}
+
+			data, shape := encode(tt.layout)
+			got, err := decoder.Decode(data, shape, tt.transform)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".golden.json")
+			if *update {
+				encoded, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, append(encoded, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			raw, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("erro ao ler golden (rode com -update): %v", err)
+			}
+			var want []Detection
+			if err := json.Unmarshal(raw, &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("detecções diferentes do golden\n got: %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeShapeErrors(t *testing.T) {
+	decoder, err := NewDecoder(Options{Layout: LayoutTransposed, NumClasses: numClasses, NumCandidates: 4, InputWidth: 640, InputHeight: 640})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		data  []float32
+		shape []int
+	}{
+		{name: "atributos errados", data: make([]float32, 6*4), shape: []int{1, 6, 4}},
+		{name: "candidatos errados", data: make([]float32, 7*5), shape: []int{1, 7, 5}},
+		{name: "lote maior que 1", data: make([]float32, 2*7*4), shape: []int{2, 7, 4}},
+		{name: "dados insuficientes", data: make([]float32, 10), shape: []int{7, 4}},
+		{name: "dimensões demais", data: make([]float32, 28), shape: []int{1, 1, 7, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decoder.Decode(tt.data, tt.shape, Stretch(640, 640, 640, 640)); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
+
+func TestNewDecoderValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "layout desconhecido", opts: Options{Layout: "yolov3", NumClasses: 1, InputWidth: 640, InputHeight: 640}},
+		{name: "sem classes", opts: Options{Layout: LayoutTransposed, InputWidth: 640, InputHeight: 640}},
+		{name: "entrada vazia", opts: Options{Layout: LayoutTransposed, NumClasses: 1}},
+		{name: "maior classe negativa", opts: Options{Layout: LayoutTransposed, NumClasses: 1, InputWidth: 640, InputHeight: 640, MaxClassID: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDecoder(tt.opts); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
+
+func TestLetterbox(t *testing.T) {
+	tests := []struct {
+		name                    string
+		frameWidth, frameHeight int
+		wantResized             [2]int
+		wantBorders             [4]int // topo, base, esquerda, direita
+		wantScale               float32
+	}{
+		{name: "16:9", frameWidth: 1280, frameHeight: 720, wantResized: [2]int{640, 360}, wantBorders: [4]int{140, 140, 0, 0}, wantScale: 2},
+		{name: "retrato", frameWidth: 480, frameHeight: 640, wantResized: [2]int{480, 640}, wantBorders: [4]int{0, 0, 80, 80}, wantScale: 1},
+		{name: "quadrado", frameWidth: 320, frameHeight: 320, wantResized: [2]int{640, 640}, wantBorders: [4]int{0, 0, 0, 0}, wantScale: 0.5},
+		{name: "borda ímpar", frameWidth: 1000, frameHeight: 561, wantResized: [2]int{640, 359}, wantBorders: [4]int{140, 141, 0, 0}, wantScale: 1.5625},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb := NewLetterbox(tt.frameWidth, tt.frameHeight, 640, 640)
+			if got := [2]int{lb.ResizedWidth, lb.ResizedHeight}; got != tt.wantResized {
+				t.Errorf("redimensionado = %v, esperado %v", got, tt.wantResized)
+			}
+			top, bottom, left, right := lb.Borders(640, 640)
+			if got := [4]int{top, bottom, left, right}; got != tt.wantBorders {
+				t.Errorf("bordas = %v, esperado %v", got, tt.wantBorders)
+			}
+			if lb.ScaleX != tt.wantScale || lb.ScaleY != tt.wantScale {
+				t.Errorf("escala = %v/%v, esperado %v", lb.ScaleX, lb.ScaleY, tt.wantScale)
+			}
+
+			// O centro da entrada volta para o centro do frame
+			box := toPixels(320, 320, 64, 64, lb.Transform)
+			center := box.Min.Add(box.Max).Div(2)
+			if dx, dy := center.X-tt.frameWidth/2, center.Y-tt.frameHeight/2; dx*dx+dy*dy > 4 {
+				t.Errorf("centro = %v, esperado próximo de (%d, %d)", center, tt.frameWidth/2, tt.frameHeight/2)
+			}
+		})
+	}
+}
+
+func TestToPixelsClipsAtEdges(t *testing.T) {
+	// Frame 1280x720 em entrada 640x640: escala 2 na horizontal, 1.125 na vertical
+	stretch := Stretch(1280, 720, 640, 640)
+
+	tests := []struct {
+		name         string
+		cx, cy, w, h float32
+		want         image.Rectangle
+	}{
+		{name: "dentro do frame", cx: 320, cy: 320, w: 100, h: 200, want: image.Rect(540, 247, 740, 472)},
+		{name: "cortada no topo e à direita", cx: 630, cy: 10, w: 40, h: 40, want: image.Rect(1220, 0, 1280, 33)},
+		{name: "cortada à esquerda e na base", cx: 5, cy: 635, w: 30, h: 20, want: image.Rect(0, 703, 40, 720)},
+		{name: "maior que o frame", cx: 320, cy: 320, w: 800, h: 800, want: image.Rect(0, 0, 1280, 720)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toPixels(tt.cx, tt.cy, tt.w, tt.h, stretch); got != tt.want {
+				t.Errorf("caixa = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeClassRules(t *testing.T) {
+	base := Options{
+		Layout:              LayoutTransposed,
+		NumClasses:          numClasses,
+		InputWidth:          640,
+		InputHeight:         640,
+		ConfidenceThreshold: 0.25,
+		MaxClassID:          numClasses - 1,
+		MinObjectSize:       20,
+	}
+
+	tests := []struct {
+		name      string
+		configure func(*Options)
+		want      []int // classes detectadas, na ordem dos candidatos
+	}{
+		{name: "sem regras", configure: func(*Options) {}, want: []int{1, 0}},
+		{
+			name:      "max_class_id 0 mantém só a classe 0",
+			configure: func(o *Options) { o.MaxClassID = 0 },
+			want:      []int{0},
+		},
+		{
+			name:      "classe desabilitada",
+			configure: func(o *Options) { o.ClassEnabled = []bool{true, false, true} },
+			want:      []int{0},
+		},
+		{
+			name:      "limiar por classe",
+			configure: func(o *Options) { o.ClassThresholds = []float32{0.7, 0.25, 0.25} },
+			want:      []int{1},
+		},
+		{
+			name: "tamanho mínimo por classe",
+			// A caixa pequena (classe 0, 10 px de largura no frame) passa com limite 5
+			configure: func(o *Options) { o.ClassMinSizes = []int{5, 20, 20} },
+			want:      []int{1, 0, 0},
+		},
+		{
+			name:      "remapeamento junta classes",
+			configure: func(o *Options) { o.Remap = []int{2, 1, 2} },
+			want:      []int{1, 2},
+		},
+		{
+			name: "regras valem para o ID remapeado",
+			configure: func(o *Options) {
+				o.Remap = []int{2, 1, 2}
+				o.ClassEnabled = []bool{true, true, false}
+			},
+			want: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			tt.configure(&opts)
+			decoder, err := NewDecoder(opts)
+			if err != nil {
+				t.Fatalf("NewDecoder: %v", err)
+			}
+
+			data, shape := encode(LayoutTransposed)
+			detections, err := decoder.Decode(data, shape, Stretch(1280, 720, 640, 640))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			got := make([]int, len(detections))
+			for i, det := range detections {
+				got[i] = det.ClassID
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classes = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
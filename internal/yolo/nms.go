@@ -0,0 +1,142 @@
+package yolo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"poc-camera/internal/tracking"
+)
+
+// Estratégias de Non-Maximum Suppression
+const (
+	// NMSGlobal suprime caixas sobrepostas entre todas as classes
+	NMSGlobal = "global"
+	// NMSClassAware suprime apenas caixas da mesma classe (pessoa não
+	// elimina a bolsa que ela segura)
+	NMSClassAware = "class_aware"
+	// NMSSoft reduz a confiança das caixas sobrepostas da mesma classe em vez
+	// de descartá-las (Soft-NMS gaussiano)
+	NMSSoft = "soft"
+)
+
+// NMS remove detecções duplicadas
+type NMS struct {
+	Strategy       string
+	IoUThreshold   float64 // sobreposição a partir da qual NMSGlobal/NMSClassAware suprimem
+	ScoreThreshold float32 // confiança mínima das caixas decaídas pelo Soft-NMS
+	SoftSigma      float64 // decaimento do Soft-NMS: score × exp(-IoU²/σ)
+	MaxDetections  int     // máximo de detecções mantidas (0 = sem limite)
+
+	// Limiares por ID final de classe, os mesmos do Decoder, no lugar de
+	// ScoreThreshold (nil = ScoreThreshold para todas as classes)
+	ClassScoreThresholds []float32
+}
+
+// Validate verifica a estratégia e os parâmetros
+func (n NMS) Validate() error {
+	switch n.Strategy {
+	case NMSGlobal, NMSClassAware:
+	case NMSSoft:
+		if n.SoftSigma <= 0 {
+			return fmt.Errorf("sigma do Soft-NMS deve ser positivo (atual: %g)", n.SoftSigma)
+		}
+	default:
+		return fmt.Errorf("estratégia de NMS desconhecida: %q", n.Strategy)
+	}
+	if n.MaxDetections < 0 {
+		return fmt.Errorf("máximo de detecções não pode ser negativo (atual: %d)", n.MaxDetections)
+	}
+	return nil
+}
+
+// Apply suprime as duplicatas e aplica o limite de detecções. As detecções
+// já devem ter passado pelos limiares de confiança do Decoder; o resultado
+// fica ordenado por confiança decrescente.
+func (n NMS) Apply(detections []Detection) []Detection {
+	// Cópia ordenada: o Soft-NMS altera as confianças
+	candidates := append([]Detection(nil), detections...)
+	sortByConfidence(candidates)
+
+	var kept []Detection
+	if n.Strategy == NMSSoft {
+		kept = n.soft(candidates)
+	} else {
+		kept = n.hard(candidates)
+	}
+	return Limit(kept, n.MaxDetections)
+}
+
+// hard mantém cada caixa que não sobrepõe uma caixa já mantida
+func (n NMS) hard(candidates []Detection) []Detection {
+	var kept []Detection
+	for _, det := range candidates {
+		suppressed := false
+		for _, k := range kept {
+			if n.Strategy == NMSClassAware && k.ClassID != det.ClassID {
+				continue
+			}
+			if tracking.IoU(k.Box, det.Box) > n.IoUThreshold {
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			kept = append(kept, det)
+		}
+	}
+	return kept
+}
+
+// soft escolhe a caixa de maior confiança, decai as sobrepostas da mesma
+// classe e repete até não restar candidato acima do limiar da sua classe
+func (n NMS) soft(candidates []Detection) []Detection {
+	var kept []Detection
+	for len(candidates) > 0 {
+		best := candidates[0]
+		kept = append(kept, best)
+
+		remaining := candidates[:0]
+		for _, det := range candidates[1:] {
+			if det.ClassID == best.ClassID {
+				if iou := tracking.IoU(best.Box, det.Box); iou > 0 {
+					det.Confidence *= float32(math.Exp(-iou * iou / n.SoftSigma))
+					if det.Confidence < n.scoreThreshold(det.ClassID) {
+						continue
+					}
+				}
+			}
+			remaining = append(remaining, det)
+		}
+		candidates = remaining
+		sortByConfidence(candidates)
+	}
+	return kept
+}
+
+// scoreThreshold retorna a confiança mínima após o decaimento para a classe
+func (n NMS) scoreThreshold(classID int) float32 {
+	if classID >= 0 && classID < len(n.ClassScoreThresholds) {
+		return n.ClassScoreThresholds[classID]
+	}
+	return n.ScoreThreshold
+}
+
+// Limit mantém as max detecções de maior confiança (max 0 = todas).
+// Usado diretamente em saídas que já vêm sem duplicatas.
+func Limit(detections []Detection, max int) []Detection {
+	if max <= 0 || len(detections) <= max {
+		return detections
+	}
+	sorted := append([]Detection(nil), detections...)
+	sortByConfidence(sorted)
+	return sorted[:max]
+}
+
+// sortByConfidence ordena por confiança decrescente, preservando a ordem
+// original em empates
+func sortByConfidence(detections []Detection) {
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Confidence > detections[j].Confidence
+	})
+}
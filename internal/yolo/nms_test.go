@@ -0,0 +1,115 @@
+package yolo
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+// Pessoa segurando uma bolsa, uma duplicata da pessoa e uma segunda pessoa
+// parcialmente sobreposta (IoU ≈ 0.43 com a primeira)
+var nmsDetections = []Detection{
+	{ClassID: 0, Confidence: 0.9, Box: image.Rect(0, 0, 100, 200)},
+	{ClassID: 1, Confidence: 0.8, Box: image.Rect(5, 20, 95, 200)},
+	{ClassID: 0, Confidence: 0.7, Box: image.Rect(2, 2, 102, 202)},
+	{ClassID: 0, Confidence: 0.6, Box: image.Rect(40, 0, 140, 200)},
+}
+
+func TestNMSStrategies(t *testing.T) {
+	tests := []struct {
+		name string
+		nms  NMS
+		want []int // índices em nmsDetections, por confiança decrescente
+	}{
+		{name: "global", nms: NMS{Strategy: NMSGlobal, IoUThreshold: 0.4}, want: []int{0}},
+		{name: "por classe mantém a bolsa", nms: NMS{Strategy: NMSClassAware, IoUThreshold: 0.4}, want: []int{0, 1}},
+		{name: "soft mantém a sobreposição parcial", nms: NMS{Strategy: NMSSoft, ScoreThreshold: 0.25, SoftSigma: 0.5}, want: []int{0, 1, 3}},
+		{name: "limite de detecções", nms: NMS{Strategy: NMSSoft, ScoreThreshold: 0.25, SoftSigma: 0.5, MaxDetections: 2}, want: []int{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.nms.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			got := tt.nms.Apply(nmsDetections)
+
+			var gotBoxes, wantBoxes []image.Rectangle
+			for _, det := range got {
+				gotBoxes = append(gotBoxes, det.Box)
+			}
+			for _, i := range tt.want {
+				wantBoxes = append(wantBoxes, nmsDetections[i].Box)
+			}
+			if !reflect.DeepEqual(gotBoxes, wantBoxes) {
+				t.Errorf("caixas = %v, esperado %v", gotBoxes, wantBoxes)
+			}
+		})
+	}
+}
+
+func TestSoftNMSDecay(t *testing.T) {
+	nms := NMS{Strategy: NMSSoft, ScoreThreshold: 0.25, SoftSigma: 0.5}
+	got := nms.Apply(nmsDetections)
+
+	// A bolsa não sofre decaimento; a segunda pessoa cai de 0.6 para ~0.42
+	if got[1].Confidence != 0.8 {
+		t.Errorf("confiança da bolsa = %v, esperado 0.8", got[1].Confidence)
+	}
+	if c := got[2].Confidence; c < 0.40 || c > 0.43 {
+		t.Errorf("confiança decaída = %v, esperado ~0.42", c)
+	}
+	if nmsDetections[3].Confidence != 0.6 {
+		t.Error("Apply alterou as detecções de entrada")
+	}
+}
+
+func TestSoftNMSClassThresholds(t *testing.T) {
+	// A duplicata da pessoa decai de 0.7 para ~0.11: cai no limiar global,
+	// mas sobrevive ao limiar menor da classe pessoa
+	tests := []struct {
+		name       string
+		thresholds []float32
+		want       []int
+	}{
+		{name: "limiar global", want: []int{0, 1, 3}},
+		{name: "limiar menor da classe", thresholds: []float32{0.05, 0.25}, want: []int{0, 1, 3, 2}},
+		{name: "limiar maior da classe", thresholds: []float32{0.5, 0.25}, want: []int{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nms := NMS{Strategy: NMSSoft, ScoreThreshold: 0.25, SoftSigma: 0.5, ClassScoreThresholds: tt.thresholds}
+			got := nms.Apply(nmsDetections)
+
+			var gotBoxes, wantBoxes []image.Rectangle
+			for _, det := range got {
+				gotBoxes = append(gotBoxes, det.Box)
+			}
+			for _, i := range tt.want {
+				wantBoxes = append(wantBoxes, nmsDetections[i].Box)
+			}
+			if !reflect.DeepEqual(gotBoxes, wantBoxes) {
+				t.Errorf("caixas = %v, esperado %v", gotBoxes, wantBoxes)
+			}
+		})
+	}
+}
+
+func TestNMSValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		nms  NMS
+	}{
+		{name: "estratégia desconhecida", nms: NMS{Strategy: "fast"}},
+		{name: "soft sem sigma", nms: NMS{Strategy: NMSSoft}},
+		{name: "limite negativo", nms: NMS{Strategy: NMSGlobal, MaxDetections: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.nms.Validate(); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package yolo
+
+// Transform mapeia coordenadas da entrada da rede de volta para o frame
+// original: frame = (rede - Pad) × Scale
+type Transform struct {
+	ScaleX, ScaleY float32 // pixels do frame por pixel da entrada
+	PadX, PadY     int     // bordas adicionadas à esquerda e no topo da entrada
+	FrameWidth     int
+	FrameHeight    int
+}
+
+// Stretch é o redimensionamento simples para a entrada, que distorce frames
+// com proporção diferente da entrada (ex: 16:9 em 640x640)
+func Stretch(frameWidth, frameHeight, inputWidth, inputHeight int) Transform {
+	return Transform{
+		ScaleX:      float32(frameWidth) / float32(inputWidth),
+		ScaleY:      float32(frameHeight) / float32(inputHeight),
+		FrameWidth:  frameWidth,
+		FrameHeight: frameHeight,
+	}
+}
+
+// Letterbox redimensiona preservando a proporção e centraliza o frame na
+// entrada, preenchendo o restante com bordas (como no treino da Ultralytics)
+type Letterbox struct {
+	Transform
+	ResizedWidth  int // dimensões do frame redimensionado, antes das bordas
+	ResizedHeight int
+}
+
+// NewLetterbox calcula o redimensionamento e as bordas do letterbox
+func NewLetterbox(frameWidth, frameHeight, inputWidth, inputHeight int) Letterbox {
+	scale := min(float64(inputWidth)/float64(frameWidth), float64(inputHeight)/float64(frameHeight))
+	resizedWidth := min(inputWidth, int(float64(frameWidth)*scale+0.5))
+	resizedHeight := min(inputHeight, int(float64(frameHeight)*scale+0.5))
+
+	inverse := float32(1 / scale)
+	return Letterbox{
+		Transform: Transform{
+			ScaleX:      inverse,
+			ScaleY:      inverse,
+			PadX:        (inputWidth - resizedWidth) / 2,
+			PadY:        (inputHeight - resizedHeight) / 2,
+			FrameWidth:  frameWidth,
+			FrameHeight: frameHeight,
+		},
+		ResizedWidth:  resizedWidth,
+		ResizedHeight: resizedHeight,
+	}
+}
+
+// Borders retorna as bordas (topo, base, esquerda, direita) a adicionar
+// ao frame redimensionado para completar a entrada
+func (lb Letterbox) Borders(inputWidth, inputHeight int) (top, bottom, left, right int) {
+	top, left = lb.PadY, lb.PadX
+	bottom = inputHeight - lb.ResizedHeight - top
+	right = inputWidth - lb.ResizedWidth - left
+	return top, bottom, left, right
+}
@@ -2,17 +2,30 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
+	"log/slog"
 	"math"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 	"time"
 
 	"gocv.io/x/gocv"
 	"poc-camera/config"
+	"poc-camera/internal/alert"
+	"poc-camera/internal/clock"
+	"poc-camera/internal/incident"
+	"poc-camera/internal/logging"
+	"poc-camera/internal/metrics"
 	"poc-camera/internal/shoplifting"
+	"poc-camera/internal/source"
+	"poc-camera/internal/yolo"
 )
 
 func init() {
@@ -34,7 +47,11 @@ type DetectionResult struct {
 // YOLODetector encapsula a lógica de detecção
 type YOLODetector struct {
 	net        gocv.Net
-	classNames []string
+	decoder    *yolo.Decoder
+	nms        yolo.NMS
+	padColor   color.RGBA
+	classNames []string // nomes do modelo
+	labels     []string // rótulos após class_remap
 	config     *config.Config
 }
 
@@ -51,9 +68,11 @@ func NewYOLODetectorAdapter(detector *YOLODetector) *YOLODetectorAdapter {
 // Detect implementa a interface shoplifting.ObjectDetector
 func (adapter *YOLODetectorAdapter) Detect(img gocv.Mat) []shoplifting.DetectionResult {
 	// Chama o detector original
-	originalResults := adapter.detector.Detect(img)
+	return toShopliftingResults(adapter.detector.Detect(img))
+}
 
-	// Converte para o tipo do package shoplifting
+// toShopliftingResults converte detecções para o tipo do package shoplifting
+func toShopliftingResults(originalResults []DetectionResult) []shoplifting.DetectionResult {
 	var results []shoplifting.DetectionResult
 	for _, orig := range originalResults {
 		results = append(results, shoplifting.DetectionResult{
@@ -67,6 +86,23 @@ func (adapter *YOLODetectorAdapter) Detect(img gocv.Mat) []shoplifting.Detection
 	return results
 }
 
+// newAlert converte um comportamento suspeito em alerta para os sinks
+func newAlert(behavior shoplifting.SuspiciousBehavior, frame int, sourceName string, img gocv.Mat, timestamp time.Time) alert.Alert {
+	return alert.Alert{
+		Type:        behavior.Type,
+		Confidence:  behavior.Confidence,
+		Description: behavior.Description,
+		Details:     behavior.Details,
+		PersonID:    behavior.PersonID,
+		Location:    alert.Point{X: behavior.Location.X, Y: behavior.Location.Y},
+		Timestamp:   timestamp,
+		Frame:       frame,
+		Source:      sourceName,
+		FrameWidth:  img.Cols(),
+		FrameHeight: img.Rows(),
+	}
+}
+
 // NewYOLODetector cria um novo detector YOLO
 func NewYOLODetector(cfg *config.Config) (*YOLODetector, error) {
 	// Carrega a rede neural
@@ -88,10 +124,62 @@ func NewYOLODetector(cfg *config.Config) (*YOLODetector, error) {
 	if err != nil {
 		return nil, fmt.Errorf("erro ao carregar classes: %v", err)
 	}
+	if err := cfg.ValidateClassNames(classNames); err != nil {
+		return nil, err
+	}
+
+	// Limiares, allow/deny e remapeamento por classe
+	classes, err := cfg.ResolveClasses(classNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decodificador da saída no layout do modelo
+	decoder, err := yolo.NewDecoder(yolo.Options{
+		Layout:              cfg.OutputLayout,
+		NumClasses:          len(classNames),
+		NumCandidates:       cfg.NumDetections,
+		InputWidth:          cfg.InputSize,
+		InputHeight:         cfg.InputSize,
+		ConfidenceThreshold: cfg.ConfidenceThreshold,
+		MaxClassID:          cfg.MaxValidClassID,
+		MinObjectSize:       cfg.MinObjectSize,
+		Remap:               classes.Remap,
+		ClassEnabled:        classes.Enabled,
+		ClassThresholds:     classes.Thresholds,
+		ClassMinSizes:       classes.MinSizes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar decodificador: %v", err)
+	}
+
+	// Supressão de duplicatas
+	nms := yolo.NMS{
+		Strategy:       cfg.NMSStrategy,
+		IoUThreshold:   float64(cfg.NMSThreshold),
+		ScoreThreshold: cfg.ConfidenceThreshold,
+		SoftSigma:      cfg.NMSSoftSigma,
+		MaxDetections:  cfg.MaxDetections,
+
+		ClassScoreThresholds: classes.Thresholds,
+	}
+	if err := nms.Validate(); err != nil {
+		return nil, fmt.Errorf("erro ao configurar NMS: %v", err)
+	}
+
+	// Cor das bordas do letterbox
+	padColor, err := cfg.LetterboxRGB()
+	if err != nil {
+		return nil, err
+	}
 
 	return &YOLODetector{
 		net:        net,
+		decoder:    decoder,
+		nms:        nms,
+		padColor:   padColor,
 		classNames: classNames,
+		labels:     classes.Names,
 		config:     cfg,
 	}, nil
 }
@@ -101,151 +189,138 @@ func (d *YOLODetector) Close() {
 	d.net.Close()
 }
 
-// Detect executa detecção em uma imagem
-func (d *YOLODetector) Detect(img gocv.Mat) []DetectionResult {
-	// Prepara entrada para o modelo
-	blob := gocv.BlobFromImage(img, 1.0/255.0, image.Pt(d.config.InputSize, d.config.InputSize),
-		gocv.NewScalar(0, 0, 0, 0), true, false)
-	defer blob.Close()
+// ClassNames retorna os nomes das classes do modelo, indexados pelo ID
+func (d *YOLODetector) ClassNames() []string {
+	return d.classNames
+}
 
-	// Executa inferência
-	d.net.SetInput(blob, "")
-	output := d.net.Forward("")
-	defer output.Close()
+// Labels retorna os rótulos das classes após o class_remap, indexados pelo ID
+func (d *YOLODetector) Labels() []string {
+	return d.labels
+}
 
-	// Processa detecções
-	return d.processDetections(output, img.Cols(), img.Rows())
+// Detect executa detecção em uma imagem
+func (d *YOLODetector) Detect(img gocv.Mat) []DetectionResult {
+	return d.DetectBatch([]gocv.Mat{img})[0]
 }
 
-// processDetections converte saída do modelo em detecções válidas
-func (d *YOLODetector) processDetections(output gocv.Mat, frameWidth, frameHeight int) []DetectionResult {
-	data, _ := output.DataPtrFloat32()
+// DetectBatch executa uma única inferência para várias imagens, empilhadas
+// em um blob [B, 3, H, W]. Lotes maiores que 1 exigem um modelo ONNX
+// exportado com batch dinâmico (verificado por CheckBatch).
+func (d *YOLODetector) DetectBatch(imgs []gocv.Mat) [][]DetectionResult {
+	results := make([][]DetectionResult, len(imgs))
+
+	// Pré-processa cada imagem (stretch ou letterbox)
+	inputs := make([]gocv.Mat, len(imgs))
+	transforms := make([]yolo.Transform, len(imgs))
+	for i, img := range imgs {
+		input, transform, release := d.prepare(img)
+		defer release()
+		inputs[i], transforms[i] = input, transform
+	}
 
-	var rawDetections []DetectionResult
-	scaleX := float32(frameWidth) / float32(d.config.InputSize)
-	scaleY := float32(frameHeight) / float32(d.config.InputSize)
+	// Executa inferência
+	output := d.forward(inputs)
+	defer output.Close()
 
-	// Processa todas as detecções
-	for i := 0; i < d.config.NumDetections; i++ {
-		detection := d.parseDetection(data, i, scaleX, scaleY, frameWidth, frameHeight)
-		if detection != nil {
-			rawDetections = append(rawDetections, *detection)
-		}
+	data, err := output.DataPtrFloat32()
+	if err != nil {
+		slog.Warn("Erro ao ler saída do modelo", logging.Err(err))
+		return results
+	}
+	shape := output.Size()
+	if len(shape) != 3 || shape[0] != len(imgs) {
+		slog.Warn("Saída do modelo não corresponde ao lote", slog.Any("shape", shape), slog.Int("batch", len(imgs)))
+		return results
 	}
 
-	// Aplica Non-Maximum Suppression
-	return d.applyNMS(rawDetections)
+	// Processa detecções de cada imagem do lote
+	perImage := len(data) / len(imgs)
+	for i := range imgs {
+		results[i] = d.processDetections(data[i*perImage:(i+1)*perImage], shape[1:], transforms[i])
+	}
+	return results
 }
 
-// parseDetection extrai uma detecção individual dos dados brutos
-func (d *YOLODetector) parseDetection(data []float32, index int, scaleX, scaleY float32, frameWidth, frameHeight int) *DetectionResult {
-	// Extrai coordenadas (formato transposto)
-	centerX := data[0*d.config.NumDetections + index]
-	centerY := data[1*d.config.NumDetections + index]
-	width := data[2*d.config.NumDetections + index]
-	height := data[3*d.config.NumDetections + index]
-
-	// Encontra classe com maior confiança
-	classID, confidence := d.findBestClass(data, index)
-
-	// Valida detecção
-	if classID < 0 || classID > d.config.MaxValidClassID || confidence < d.config.ConfidenceThreshold {
-		return nil
-	}
+// forward empilha as entradas em um blob [B, 3, H, W] e executa a rede
+func (d *YOLODetector) forward(inputs []gocv.Mat) gocv.Mat {
+	size := d.config.InputSize
+	blob := gocv.NewMat()
+	defer blob.Close()
+	gocv.BlobFromImages(inputs, &blob, 1.0/255.0, image.Pt(size, size),
+		gocv.NewScalar(0, 0, 0, 0), true, false, gocv.MatTypeCV32F)
 
-	// Converte coordenadas para pixels
-	box := d.convertToPixelCoordinates(centerX, centerY, width, height,
-		scaleX, scaleY, frameWidth, frameHeight)
+	d.net.SetInput(blob, "")
+	return d.net.Forward("")
+}
 
-	// Filtra objetos muito pequenos
-	if box.Dx() < d.config.MinObjectSize || box.Dy() < d.config.MinObjectSize {
-		return nil
+// CheckBatch executa uma inferência com n imagens vazias e verifica se a
+// saída traz uma entrada por imagem. Modelos ONNX exportados com batch fixo
+// não atendem lotes maiores que 1.
+func (d *YOLODetector) CheckBatch(n int) error {
+	size := d.config.InputSize
+	inputs := make([]gocv.Mat, n)
+	for i := range inputs {
+		inputs[i] = gocv.NewMatWithSize(size, size, gocv.MatTypeCV8UC3)
+		defer inputs[i].Close()
 	}
 
-	// Cria label
-	label := fmt.Sprintf("%s: %.2f", d.classNames[classID], confidence)
-
-	return &DetectionResult{
-		ClassID:    classID,
-		Confidence: confidence,
-		Box:        box,
-		Label:      label,
+	output := d.forward(inputs)
+	defer output.Close()
+	if shape := output.Size(); len(shape) != 3 || shape[0] != n {
+		return fmt.Errorf("modelo %s não aceita lote de %d imagens (saída %v): exporte o ONNX com batch dinâmico ou use detector_batch_size 1",
+			d.config.ObjectDetectionModel, n, shape)
 	}
+	return nil
 }
 
-// findBestClass encontra a classe com maior confiança
-func (d *YOLODetector) findBestClass(data []float32, index int) (int, float32) {
-	var bestClassID int
-	var maxScore float32
-
-	// Verifica se temos dados suficientes
-	dataLength := len(data)
-	maxIndex := (d.config.NumAttributes - 1) * d.config.NumDetections + index
-
-	if maxIndex >= dataLength {
-		return 0, 0.0
+// prepare aplica o pré-processamento e retorna a entrada da rede, a
+// transformação para voltar ao frame e a função que libera a entrada
+func (d *YOLODetector) prepare(img gocv.Mat) (gocv.Mat, yolo.Transform, func()) {
+	size := d.config.InputSize
+	if !d.config.Letterbox {
+		return img, yolo.Stretch(img.Cols(), img.Rows(), size, size), func() {}
 	}
 
-	for j := 4; j < d.config.NumAttributes; j++ {
-		dataIndex := j*d.config.NumDetections + index
-		if dataIndex < dataLength {
-			score := data[dataIndex]
-			if score > maxScore {
-				maxScore = score
-				bestClassID = j - 4 // classes 0-364
-			}
-		}
-	}
+	// Letterbox: redimensiona preservando a proporção e completa com bordas
+	lb := yolo.NewLetterbox(img.Cols(), img.Rows(), size, size)
 
-	return bestClassID, maxScore
-}
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, image.Pt(lb.ResizedWidth, lb.ResizedHeight), 0, 0, gocv.InterpolationLinear)
 
-// convertToPixelCoordinates converte coordenadas normalizadas para pixels
-func (d *YOLODetector) convertToPixelCoordinates(centerX, centerY, width, height, scaleX, scaleY float32, frameWidth, frameHeight int) image.Rectangle {
-	// Converte para coordenadas de pixel
-	pixelCenterX := int(centerX * scaleX)
-	pixelCenterY := int(centerY * scaleY)
-	pixelWidth := int(width * scaleX)
-	pixelHeight := int(height * scaleY)
-
-	// Calcula coordenadas da caixa
-	left := pixelCenterX - pixelWidth/2
-	top := pixelCenterY - pixelHeight/2
-
-	// Garante que está dentro dos limites da imagem
-	left = max(0, left)
-	top = max(0, top)
-	right := min(frameWidth, left+pixelWidth)
-	bottom := min(frameHeight, top+pixelHeight)
-
-	return image.Rect(left, top, right, bottom)
+	padded := gocv.NewMat()
+	top, bottom, left, right := lb.Borders(size, size)
+	gocv.CopyMakeBorder(resized, &padded, top, bottom, left, right, gocv.BorderConstant, d.padColor)
+	return padded, lb.Transform, func() { padded.Close() }
 }
 
-// applyNMS aplica Non-Maximum Suppression para remover detecções duplicadas
-func (d *YOLODetector) applyNMS(detections []DetectionResult) []DetectionResult {
-	if len(detections) == 0 {
-		return detections
+// processDetections converte a saída de uma imagem em detecções válidas.
+// transform mapeia as caixas da entrada da rede de volta para o frame.
+func (d *YOLODetector) processDetections(data []float32, shape []int, transform yolo.Transform) []DetectionResult {
+	decoded, err := d.decoder.Decode(data, shape, transform)
+	if err != nil {
+		slog.Warn("Erro ao decodificar saída do modelo", logging.Err(err))
+		return nil
 	}
 
-	// Prepara dados para NMS
-	var boxes []image.Rectangle
-	var confidences []float32
-
-	for _, det := range detections {
-		boxes = append(boxes, det.Box)
-		confidences = append(confidences, det.Confidence)
+	// Saídas end-to-end já vêm sem duplicatas: só aplica o limite
+	if d.decoder.NMSFree() {
+		decoded = yolo.Limit(decoded, d.nms.MaxDetections)
+	} else {
+		decoded = d.nms.Apply(decoded)
 	}
 
-	// Aplica NMS
-	indices := gocv.NMSBoxes(boxes, confidences, d.config.ConfidenceThreshold, d.config.NMSThreshold)
-
-	// Retorna apenas detecções válidas
-	var result []DetectionResult
-	for _, idx := range indices {
-		result = append(result, detections[idx])
+	detections := make([]DetectionResult, len(decoded))
+	for i, det := range decoded {
+		detections[i] = DetectionResult{
+			ClassID:    det.ClassID,
+			Confidence: det.Confidence,
+			Box:        det.Box,
+			Label:      fmt.Sprintf("%s: %.2f", d.labels[det.ClassID], det.Confidence),
+		}
 	}
-
-	return result
+	return detections
 }
 
 // DrawDetections desenha as detecções na imagem
@@ -313,31 +388,35 @@ func loadClassNames(filename string) ([]string, error) {
 	return lines, scanner.Err()
 }
 
-// setupCamera inicializa e configura a câmera (com fallback para múltiplos índices)
-func setupCamera() (*gocv.VideoCapture, error) {
-	// Tenta diferentes índices de câmera
-	for i := 0; i < 4; i++ {
-		fmt.Printf("🔍 Tentando câmera índice %d...\n", i)
-		webcam, err := gocv.VideoCaptureDevice(i)
-		if err != nil {
-			fmt.Printf("❌ Câmera %d: %v\n", i, err)
-			continue
-		}
+// setupSource abre a fonte de frames configurada (câmera, arquivo, stream ou imagens)
+func setupSource(cfg *config.Config) (source.FrameSource, error) {
+	src, err := source.Open(cfg.SourceType, cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Fonte de vídeo", logging.Icon("🎥"), logging.Camera(cfg.CameraID), slog.String("source", src.Name()))
+	return src, nil
+}
 
-		// Testa se consegue capturar um frame
-		testImg := gocv.NewMat()
-		if ok := webcam.Read(&testImg); ok && !testImg.Empty() {
-			testImg.Close()
-			fmt.Printf("✅ Câmera %d funcionando!\n", i)
-			return webcam, nil
+// setupClock escolhe a origem do tempo da análise. No modo frame (padrão
+// para arquivos e imagens) retorna o relógio que deve avançar a cada frame
+// lido; no modo wall retorna nil e a análise usa o relógio do sistema.
+func setupClock(cfg *config.Config, src source.FrameSource, detector *shoplifting.ShopliftingDetector) *clock.Frame {
+	mode := cfg.ClockSource
+	if mode == "auto" {
+		mode = "wall"
+		if !src.Live() {
+			mode = "frame"
 		}
-
-		testImg.Close()
-		webcam.Close()
-		fmt.Printf("⚠️  Câmera %d não consegue capturar frames\n", i)
+	}
+	if mode != "frame" {
+		return nil
 	}
 
-	return nil, fmt.Errorf("nenhuma câmera funcional encontrada (testados índices 0-3)")
+	frameClock := clock.NewFrame(time.Now(), cfg.FrameRate)
+	detector.SetClock(frameClock)
+	slog.Info("Tempo da análise guiado pelos timestamps dos frames", logging.Icon("⏱️ "), logging.Camera(cfg.CameraID))
+	return frameClock
 }
 
 // setupWindow cria e configura a janela de visualização
@@ -347,159 +426,244 @@ func setupWindow(title string) *gocv.Window {
 	return window
 }
 
-// handleInput verifica input do usuário para sair
-func handleInput(window *gocv.Window) bool {
-	key := window.WaitKey(30)
+// handleInput verifica input do usuário para sair. A tecla é lida uma vez
+// para todas as janelas.
+func handleInput(windows []*gocv.Window) bool {
+	key := windows[0].WaitKey(30)
 
 	// ESC ou Q para sair
 	if key == 27 || key == 'q' || key == 'Q' {
 		return true
 	}
 
-	// Verifica se janela foi fechada
-	if !window.IsOpen() {
-		return true
+	// Verifica se alguma janela foi fechada
+	for _, window := range windows {
+		if !window.IsOpen() {
+			return true
+		}
 	}
 
 	return false
 }
 
-// Funções utilitárias
-func max(a, b int) int {
-	if a > b {
-		return a
+func main() {
+	// Configuração para shoplifting detection
+	// (padrões → arquivo → variáveis de ambiente → flags)
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
 	}
-	return b
-}
+	if err != nil {
+		fmt.Printf("❌ Erro na configuração: %v\n", err)
+		os.Exit(2)
+	}
+	appConfig = cfg
 
-func min(a, b int) int {
-	if a < b {
-		return a
+	// Logger estruturado (console, text ou json) usado por todos os pacotes
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fmt.Printf("❌ Erro na configuração: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	// Com anotações ground-truth, reproduz a fonte e mede a qualidade
+	if appConfig.EvalAnnotations != "" {
+		runEvaluation()
+		return
 	}
-	return b
-}
 
-func main() {
-	// Configuração para shoplifting detection
-	appConfig = config.DefaultConfig()
 	runShopliftingDetection()
 }
 
-// runShopliftingDetection executa detecção de shoplifting
+// runShopliftingDetection executa detecção de shoplifting em todas as câmeras
 func runShopliftingDetection() {
-	// Inicializa detector de objetos base
-	objectDetector, err := NewYOLODetector(appConfig)
+	// Métricas são sempre coletadas; o servidor HTTP só sobe com metrics_addr
+	stats := metrics.New()
+	if appConfig.MetricsAddr != "" {
+		server, err := stats.Serve(appConfig.MetricsAddr)
+		if err != nil {
+			slog.Error("Erro nas métricas", logging.Err(err))
+			os.Exit(1)
+		}
+		defer server.Close()
+		slog.Info("Métricas Prometheus disponíveis", logging.Icon("📈"), slog.String("url", "http://"+appConfig.MetricsAddr+"/metrics"))
+	}
+
+	// Inicializa o pool de detectores de objetos, compartilhado entre as câmeras
+	detectorPool, err := NewYOLODetectorPool(appConfig, stats)
 	if err != nil {
-		fmt.Printf("❌ Erro ao inicializar detector de objetos: %v\n", err)
+		slog.Error("Erro ao inicializar detector de objetos", logging.Err(err))
 		os.Exit(1)
 	}
-	defer objectDetector.Close()
-
-	// Cria adapter para o detector YOLO
-	detectorAdapter := NewYOLODetectorAdapter(objectDetector)
+	defer detectorPool.Close()
+	stats.SetClasses(detectorPool.Labels())
+	if detectorPool.Size() > 1 || appConfig.DetectorBatchSize > 1 {
+		slog.Info("Pool de detectores", logging.Icon("🧠"),
+			slog.Int("instances", detectorPool.Size()), slog.Int("batch_size", appConfig.DetectorBatchSize))
+	}
 
-	// Inicializa detector de shoplifting integrado
-	shopliftingDetector, err := shoplifting.NewShopliftingDetector(detectorAdapter, appConfig)
+	// Configura destinos de alertas (arquivo, webhook, MQTT)
+	alertSink, err := alert.NewFromConfig(appConfig)
 	if err != nil {
-		fmt.Printf("❌ Erro ao inicializar detector de shoplifting: %v\n", err)
+		slog.Error("Erro ao configurar alertas", logging.Err(err))
 		os.Exit(1)
 	}
-	defer shopliftingDetector.Close()
+	defer func() {
+		if err := alertSink.Close(); err != nil {
+			slog.Warn("Erro ao encerrar alertas", logging.Err(err))
+		}
+	}()
+
+	// Persiste os incidentes (mais um sink) e as observações das câmeras
+	var store *incident.Store
+	if appConfig.IncidentDB != "" {
+		store, err = incident.Open(appConfig.IncidentDB)
+		if err != nil {
+			slog.Error("Erro no banco de incidentes", logging.Err(err))
+			os.Exit(1)
+		}
+		alertSink.Add(store)
+		slog.Info("Incidentes persistidos", logging.Icon("🗄️ "), slog.String("path", appConfig.IncidentDB))
+	}
+	if alertSink.Len() > 0 {
+		slog.Info("Destinos de alerta configurados", logging.Icon("📡"), slog.Int("sinks", alertSink.Len()))
+	}
 
-	// Configura câmera
-	webcam, err := setupCamera()
+	// Configura câmeras: fonte, análise, zonas e evidências próprias
+	ready := make(chan struct{}, 1)
+	cameras, err := setupCameras(appConfig, detectorPool, detectorPool.ClassNames(), stats, ready)
 	if err != nil {
-		fmt.Printf("❌ Erro na câmera: %v\n", err)
+		slog.Error("Erro nas câmeras", logging.Err(err))
 		os.Exit(1)
 	}
-	defer webcam.Close()
+	if store != nil {
+		for _, cam := range cameras {
+			cam.store = store
+		}
+	}
+	defer func() {
+		for _, cam := range cameras {
+			cam.Close()
+		}
+	}()
+
+	// Encerra de forma limpa com SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Configura janelas (não existem em modo headless): um mosaico ou uma
+	// janela por câmera
+	var windows []*gocv.Window
+	var grid *mosaic
+	if !appConfig.Headless {
+		if appConfig.Mosaic {
+			grid = newMosaic(len(cameras), appConfig.MosaicColumns, appConfig.MosaicTileWidth)
+			defer grid.Close()
+			window := setupWindow(appConfig.WindowName)
+			defer window.Close()
+			windows = append(windows, window)
+		} else {
+			for _, cam := range cameras {
+				title := appConfig.WindowName
+				if len(cameras) > 1 {
+					title += " - " + cam.id
+				}
+				cam.window = setupWindow(title)
+				windows = append(windows, cam.window)
+			}
+		}
+	}
 
-	// Configura janela
-	window := setupWindow(appConfig.WindowName)
-	defer window.Close()
+	// Informações iniciais
+	slog.Info("SHOPLIFTING DETECTOR ATIVO", logging.Icon("🛡️ "),
+		slog.String("model", "YOLO v11"), slog.Int("cameras", len(cameras)),
+		slog.Bool("headless", appConfig.Headless))
+	if appConfig.Headless {
+		slog.Info("Modo headless: Ctrl+C ou SIGTERM para sair", logging.Icon("🖥️ "))
+	} else {
+		slog.Info("Pressione ESC ou Q para sair", logging.Icon("📱"))
+	}
 
-	// Prepara buffer para frames
-	img := gocv.NewMat()
-	defer img.Close()
+	// Captura e inferência rodam em goroutines por câmera; análise, desenho
+	// e janelas ficam nesta thread (HighGUI)
+	for _, cam := range cameras {
+		cam.frames.Start(ctx)
+	}
 
-	// Informações iniciais
-	fmt.Println("🛡️  SHOPLIFTING DETECTOR ATIVO")
-	fmt.Println("🤖 YOLO v11 Object Detection")
-	fmt.Println("👥 Detecta pessoas e comportamentos suspeitos")
-	fmt.Println("🚨 Alertas em tempo real para:")
-	fmt.Println("   • Pessoas vagueando por muito tempo")
-	fmt.Println("   • Proximidade com itens valiosos")
-	fmt.Println("   • Movimentos suspeitos")
-	fmt.Println("📱 Pressione ESC ou Q para sair")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	frameCount := 0
-	alertCount := 0
-
-	// Loop principal de detecção
-	for {
-		// Captura frame
-		if ok := webcam.Read(&img); !ok {
-			fmt.Println("❌ Erro ao ler da webcam")
-			break
+	// Loop principal de análise
+	for active := len(cameras); active > 0 && ctx.Err() == nil; {
+		processed := false
+		for _, cam := range cameras {
+			if cam.done {
+				continue
+			}
+			frame, ok, closed := cam.frames.Next()
+			if closed {
+				cam.done = true
+				active--
+				continue
+			}
+			if ok {
+				cam.process(frame, alertSink, len(windows) > 0)
+				processed = true
+			}
 		}
 
-		if img.Empty() {
+		// Sem janela não há o que mostrar: aguarda o próximo resultado
+		if len(windows) == 0 {
+			if !processed {
+				select {
+				case <-ready:
+				case <-ctx.Done():
+				}
+			}
 			continue
 		}
 
-		frameCount++
-
-		// Executa detecção de shoplifting
-		detections, suspiciousBehaviors := shopliftingDetector.DetectShoplifting(img)
-
-		// Conta alertas
-		if len(suspiciousBehaviors) > 0 {
-			alertCount += len(suspiciousBehaviors)
-
-			// Log dos comportamentos suspeitos (apenas uma vez por segundo)
-			for _, behavior := range suspiciousBehaviors {
-				if behavior.ShouldLog {
-					if behavior.Details != "" {
-						fmt.Printf("🚨 ALERTA: %s (Confiança: %.1f%%) - %s\n   📊 Detalhes: %s\n",
-							behavior.Type, behavior.Confidence*100, behavior.Description, behavior.Details)
-					} else {
-						fmt.Printf("🚨 ALERTA: %s (Confiança: %.1f%%) - %s\n",
-							behavior.Type, behavior.Confidence*100, behavior.Description)
-					}
-				}
+		// Mostra os frames novos (WaitKey também mantém as janelas responsivas)
+		for i, cam := range cameras {
+			if !cam.updated {
+				continue
+			}
+			cam.updated = false
+			if grid != nil {
+				grid.Update(i, cam.display, cam.id)
+			} else {
+				cam.window.IMShow(cam.display)
 			}
 		}
-
-		// Desenha resultados na imagem
-		shoplifting.DrawShopliftingDetections(&img, detections, suspiciousBehaviors)
-
-		// Adiciona informações de status na imagem
-		addStatusInfo(&img, frameCount, len(detections), len(suspiciousBehaviors), alertCount)
-
-		// Mostra na janela
-		window.IMShow(img)
+		if grid != nil && processed {
+			windows[0].IMShow(grid.canvas)
+		}
 
 		// Verifica input do usuário
-		if handleInput(window) {
+		if handleInput(windows) {
 			break
 		}
 	}
 
+	for _, cam := range cameras {
+		cam.frames.Stop()
+		cam.closeIncidents(alertSink)
+	}
+
+	if ctx.Err() != nil {
+		slog.Info("Sinal de encerramento recebido", logging.Icon("🛑"))
+	}
+
 	// Estatísticas finais
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("📊 ESTATÍSTICAS FINAIS:\n")
-	fmt.Printf("   • Frames processados: %d\n", frameCount)
-	fmt.Printf("   • Total de alertas: %d\n", alertCount)
-	fmt.Printf("   • Taxa de alertas: %.2f%%\n", float64(alertCount)/float64(frameCount)*100)
-	fmt.Println("👋 Detector de shoplifting encerrado")
+	for _, cam := range cameras {
+		cam.logStats()
+	}
+	slog.Info("Detector de shoplifting encerrado", logging.Icon("👋"))
 }
 
 // addStatusInfo adiciona informações de status na imagem
-func addStatusInfo(img *gocv.Mat, frameCount, detectionCount, alertCount, totalAlerts int) {
+func addStatusInfo(img *gocv.Mat, frameCount, detectionCount, alertCount, totalIncidents int) {
 	// Painel de informações no topo
-	statusText := fmt.Sprintf("Frame: %d | Deteccoes: %d | Alertas Ativos: %d | Total: %d",
-		frameCount, detectionCount, alertCount, totalAlerts)
+	statusText := fmt.Sprintf("Frame: %d | Deteccoes: %d | Alertas Ativos: %d | Incidentes: %d",
+		frameCount, detectionCount, alertCount, totalIncidents)
 
 	// Fundo semi-transparente para o texto
 	gocv.Rectangle(img,
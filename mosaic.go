@@ -0,0 +1,57 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+// mosaic compõe o último frame de cada câmera em uma grade de células 16:9
+type mosaic struct {
+	canvas     gocv.Mat
+	columns    int
+	tileWidth  int
+	tileHeight int
+}
+
+// newMosaic cria a grade para n câmeras (columns 0 = grade quase quadrada)
+func newMosaic(n, columns, tileWidth int) *mosaic {
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	columns = min(columns, n)
+	rows := (n + columns - 1) / columns
+	tileHeight := tileWidth * 9 / 16
+
+	return &mosaic{
+		canvas:     gocv.Zeros(rows*tileHeight, columns*tileWidth, gocv.MatTypeCV8UC3),
+		columns:    columns,
+		tileWidth:  tileWidth,
+		tileHeight: tileHeight,
+	}
+}
+
+// Update redimensiona o frame para a célula da câmera i e escreve o rótulo
+func (m *mosaic) Update(i int, img gocv.Mat, label string) {
+	x := (i % m.columns) * m.tileWidth
+	y := (i / m.columns) * m.tileHeight
+	tile := m.canvas.Region(image.Rect(x, y, x+m.tileWidth, y+m.tileHeight))
+	defer tile.Close()
+
+	gocv.Resize(img, &tile, image.Pt(m.tileWidth, m.tileHeight), 0, 0, gocv.InterpolationLinear)
+
+	// Rótulo no canto inferior direito (o topo tem o painel de status)
+	gocv.Rectangle(&tile, image.Rect(m.tileWidth-120, m.tileHeight-30, m.tileWidth, m.tileHeight),
+		color.RGBA{0, 0, 0, 180}, -1)
+	gocv.PutText(&tile, label,
+		image.Pt(m.tileWidth-110, m.tileHeight-10),
+		gocv.FontHersheySimplex, 0.6,
+		color.RGBA{255, 255, 0, 255}, 2)
+}
+
+// Close libera o canvas
+func (m *mosaic) Close() {
+	m.canvas.Close()
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"gocv.io/x/gocv"
+	"poc-camera/internal/logging"
+	"poc-camera/internal/metrics"
+	"poc-camera/internal/pipeline"
+	"poc-camera/internal/shoplifting"
+	"poc-camera/internal/source"
+)
+
+// capturedFrame é um frame lido da fonte, dono da sua Mat
+type capturedFrame struct {
+	img        gocv.Mat
+	number     int           // posição na fonte, contando frames descartados
+	pts        time.Duration // timestamp da fonte, se hasPTS
+	hasPTS     bool
+	capturedAt time.Time
+}
+
+// inferredFrame é um frame com as detecções do modelo. Frames pulados pelo
+// agendador chegam sem detecções (detected false).
+type inferredFrame struct {
+	capturedFrame
+	detections []shoplifting.DetectionResult
+	detected   bool
+	stride     int // passo de detecção em vigor
+}
+
+// framePipeline roda captura e inferência de uma câmera em goroutines
+// próprias. A análise, o desenho e a janela ficam com quem consome Next, na
+// thread principal (exigência do HighGUI no macOS).
+type framePipeline struct {
+	source   source.FrameSource
+	detector shoplifting.ObjectDetector
+	frames   *pipeline.Queue[capturedFrame] // captura → inferência
+	results  *pipeline.Queue[inferredFrame] // inferência → análise
+	latency  *pipeline.Latency
+	schedule *pipeline.Scheduler // usado só pela goroutine de inferência
+	metrics  *metrics.Camera
+	ready    chan<- struct{} // avisa o consumidor de novos resultados
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// newFramePipeline cria o pipeline. Fontes ao vivo descartam o frame mais
+// antigo quando um estágio atrasa; arquivos e imagens bloqueiam a leitura,
+// sem perder frames. schedule escolhe os frames que passam pela detecção e
+// stats conta as falhas de leitura. ready (compartilhado entre câmeras)
+// recebe um aviso a cada resultado e no fim da fonte.
+func newFramePipeline(src source.FrameSource, detector shoplifting.ObjectDetector, queueSize int, schedule *pipeline.Scheduler, stats *metrics.Camera, ready chan<- struct{}) *framePipeline {
+	live := src.Live()
+	return &framePipeline{
+		source:   src,
+		detector: detector,
+		frames:   pipeline.NewQueue(queueSize, live, func(f capturedFrame) { f.img.Close() }),
+		results:  pipeline.NewQueue(queueSize, live, func(f inferredFrame) { f.img.Close() }),
+		latency:  pipeline.NewLatency(),
+		schedule: schedule,
+		metrics:  stats,
+		ready:    ready,
+	}
+}
+
+// Start inicia os estágios de captura e inferência
+func (p *framePipeline) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(2)
+	go p.capture(ctx)
+	go p.infer(ctx)
+}
+
+// Next retorna o próximo frame inferido sem bloquear; closed indica que a
+// fonte acabou e todos os frames foram entregues
+func (p *framePipeline) Next() (frame inferredFrame, ok, closed bool) {
+	return p.results.TryPop()
+}
+
+// Stop encerra os estágios e libera os frames ainda nas filas
+func (p *framePipeline) Stop() {
+	p.cancel()
+	p.wg.Wait()
+	p.frames.Drain()
+	p.results.Drain()
+}
+
+// capture lê frames da fonte até o fim ou o cancelamento
+func (p *framePipeline) capture(ctx context.Context) {
+	defer p.wg.Done()
+	defer p.frames.Close()
+
+	number := 0
+	for ctx.Err() == nil {
+		img := gocv.NewMat()
+		start := time.Now()
+		if ok := p.source.Read(&img); !ok {
+			img.Close()
+			if p.source.Live() {
+				p.metrics.ReadFailure()
+			}
+			slog.Info("Sem mais frames da fonte", logging.Icon("⏹️ "), slog.String("source", p.source.Name()))
+			return
+		}
+		p.latency.Observe(pipeline.StageCapture, time.Since(start))
+
+		if img.Empty() {
+			img.Close()
+			p.metrics.ReadFailure()
+			continue
+		}
+
+		number++
+		pts, hasPTS := p.source.Timestamp()
+		p.frames.Push(ctx, capturedFrame{
+			img:        img,
+			number:     number,
+			pts:        pts,
+			hasPTS:     hasPTS,
+			capturedAt: start,
+		})
+	}
+}
+
+// infer executa o detector de objetos sobre os frames escolhidos pelo
+// agendador; os demais seguem sem detecções
+func (p *framePipeline) infer(ctx context.Context) {
+	defer p.wg.Done()
+	defer p.notify()
+	defer p.results.Close()
+
+	for {
+		frame, ok := p.frames.Pop(ctx)
+		if !ok {
+			return
+		}
+
+		result := inferredFrame{capturedFrame: frame, stride: p.schedule.Stride()}
+		if p.schedule.Due() {
+			start := time.Now()
+			result.detections = p.detector.Detect(frame.img)
+			result.detected = true
+			latency := time.Since(start)
+			p.latency.Observe(pipeline.StageInference, latency)
+			p.schedule.Observe(latency)
+		}
+
+		p.results.Push(ctx, result)
+		p.notify()
+	}
+}
+
+// notify avisa o consumidor sem bloquear (um aviso pendente basta)
+func (p *framePipeline) notify() {
+	select {
+	case p.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Dropped retorna os frames descartados entre captura e inferência e entre
+// inferência e análise
+func (p *framePipeline) Dropped() (beforeInference, beforeAnalysis int64) {
+	return p.frames.Dropped(), p.results.Dropped()
+}
+
+// addLatencyInfo mostra a última latência de cada estágio e o passo de
+// detecção no rodapé
+func addLatencyInfo(img *gocv.Mat, stages []pipeline.StageLatency, stride int) {
+	parts := make([]string, len(stages))
+	for i, s := range stages {
+		parts[i] = fmt.Sprintf("%s %dms", s.Stage, s.Last.Milliseconds())
+	}
+	if stride > 1 {
+		parts = append(parts, fmt.Sprintf("deteccao 1/%d frames", stride))
+	}
+
+	// Fonte Hershey não tem acentos
+	text := strings.NewReplacer("ê", "e", "á", "a").Replace(strings.Join(parts, " | "))
+	gocv.PutText(img, text,
+		image.Pt(10, img.Rows()-15),
+		gocv.FontHersheySimplex, 0.5,
+		color.RGBA{255, 255, 255, 255}, 1)
+}